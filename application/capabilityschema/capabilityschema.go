@@ -0,0 +1,75 @@
+// Package capabilityschema bundles the canonical JSON schemas for every
+// capability kind the SDK knows about, so a host no longer has to
+// reimplement the network/fs/env/exec/kv contracts itself and a plugin has
+// a stable, versioned target to validate against.
+package capabilityschema
+
+import (
+	"embed"
+	"fmt"
+	"path"
+)
+
+//go:embed schemas
+var schemaFS embed.FS
+
+// CurrentVersion is the semver of the schema set this build of the SDK
+// bundles. It is what DefaultRegistry reports via Version, and what
+// CapabilityValidator compares a manifest's CapabilitySchemaVersion
+// against.
+const CurrentVersion = "v1"
+
+// kinds lists every capability kind the bundled schema set covers.
+var kinds = []string{"network", "fs", "env", "exec", "kv", "icmp", "log"}
+
+// For returns the canonical JSON schema for kind at the given version, and
+// false if either is unknown.
+func For(kind, version string) (string, bool) {
+	data, err := schemaFS.ReadFile(path.Join("schemas", version, kind+".json"))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Registry is the interface application/validation.CapabilityValidator
+// expects a capability schema source to satisfy.
+type Registry interface {
+	Register(name string, capability interface{}) error
+	GetSchema(name string) (string, bool)
+	List() []string
+}
+
+// defaultRegistry serves the bundled CurrentVersion schemas and reports
+// CurrentVersion via Version, so CapabilityValidator can reject manifests
+// pinned to a newer schema version than the host bundles.
+type defaultRegistry struct{}
+
+// DefaultRegistry returns a Registry backed by the SDK's bundled,
+// CurrentVersion-tagged schemas. Register is a no-op: the bundled set is
+// fixed at build time and isn't meant to be extended at runtime.
+func DefaultRegistry() Registry {
+	return defaultRegistry{}
+}
+
+func (defaultRegistry) Register(name string, capability interface{}) error {
+	return fmt.Errorf("capabilityschema: DefaultRegistry is read-only, cannot register %q", name)
+}
+
+func (defaultRegistry) GetSchema(name string) (string, bool) {
+	return For(name, CurrentVersion)
+}
+
+func (defaultRegistry) List() []string {
+	out := make([]string, len(kinds))
+	copy(out, kinds)
+	return out
+}
+
+// Version reports the schema version defaultRegistry serves, so
+// CapabilityValidator can compare it against a manifest's pinned
+// CapabilitySchemaVersion. Satisfies the optional versionedRegistry
+// interface in application/validation.
+func (defaultRegistry) Version() string {
+	return CurrentVersion
+}
@@ -0,0 +1,52 @@
+package capabilityschema_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/application/capabilityschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFor_KnownKindAndVersion(t *testing.T) {
+	schema, ok := capabilityschema.For("network", capabilityschema.CurrentVersion)
+	assert.True(t, ok)
+	assert.Contains(t, schema, `"rules"`)
+}
+
+func TestFor_UnknownKindOrVersion(t *testing.T) {
+	_, ok := capabilityschema.For("nope", capabilityschema.CurrentVersion)
+	assert.False(t, ok)
+
+	_, ok = capabilityschema.For("network", "v999")
+	assert.False(t, ok)
+}
+
+func TestDefaultRegistry_ServesEveryBundledKind(t *testing.T) {
+	registry := capabilityschema.DefaultRegistry()
+	for _, kind := range []string{"network", "fs", "env", "exec", "kv", "icmp", "log"} {
+		schema, ok := registry.GetSchema(kind)
+		assert.True(t, ok, "kind %s", kind)
+		assert.NotEmpty(t, schema)
+	}
+	assert.ElementsMatch(t, []string{"network", "fs", "env", "exec", "kv", "icmp", "log"}, registry.List())
+}
+
+func TestFor_LogSchemaAcceptsMinLevelAndFields(t *testing.T) {
+	schema, ok := capabilityschema.For("log", capabilityschema.CurrentVersion)
+	assert.True(t, ok)
+	assert.Contains(t, schema, `"min_level"`)
+	assert.Contains(t, schema, `"fields"`)
+}
+
+func TestDefaultRegistry_RegisterIsReadOnly(t *testing.T) {
+	registry := capabilityschema.DefaultRegistry()
+	err := registry.Register("network", "whatever")
+	assert.Error(t, err)
+}
+
+func TestDefaultRegistry_ReportsCurrentVersion(t *testing.T) {
+	versioned, ok := capabilityschema.DefaultRegistry().(interface{ Version() string })
+	if assert.True(t, ok, "DefaultRegistry should satisfy an optional Version() string interface") {
+		assert.Equal(t, capabilityschema.CurrentVersion, versioned.Version())
+	}
+}
@@ -1,11 +1,13 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
 	"sync"
 
+	"github.com/reglet-dev/reglet-plugin-sdk/application/plugin/pluginevents"
 	"github.com/reglet-dev/reglet-plugin-sdk/application/schema"
 	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
 )
@@ -67,6 +69,24 @@ func DefinePlugin(def PluginDef) *PluginDefinition {
 	}
 }
 
+// Name returns the plugin's name, as given to DefinePlugin.
+func (p *PluginDefinition) Name() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.def.Name
+}
+
+// Watch subscribes to this plugin's lifecycle and runtime events - the same
+// ones emitted by the typed handler wrapper during registration and every
+// invocation (PluginRegistered, HandlerStarted/Finished, CapabilityDenied,
+// PanicRecovered, and any custom events a handler publishes via
+// pluginevents.Emit). The returned channel receives events matching filter
+// until ctx is done, at which point it's closed. Use this to observe a
+// running plugin without polling logs or Evidence output.
+func (p *PluginDefinition) Watch(ctx context.Context, filter pluginevents.Filter) <-chan pluginevents.Event {
+	return pluginevents.Watch(ctx, p.Name(), filter)
+}
+
 // Manifest returns the complete plugin manifest.
 func (p *PluginDefinition) Manifest() *entities.Manifest {
 	p.mu.RLock()
@@ -81,9 +101,15 @@ func (p *PluginDefinition) Manifest() *entities.Manifest {
 				Description: op.description,
 			}
 
-			// Generate input fields from input type (if available)
+			// Generate input fields and schema from input type (if available)
 			if op.inputType != nil {
 				opManifest.InputFields = extractFieldNames(op.inputType)
+
+				val := reflect.New(op.inputType).Elem().Interface()
+				inputSchema, err := schema.GenerateSchema(val)
+				if err == nil {
+					opManifest.InputSchema = inputSchema
+				}
 			}
 
 			// Generate output schema from output type (if available)
@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+)
+
+// timeouter matches the net.Error-style Timeout() bool convention, shared by
+// entities.TimeoutError and most network/context errors a handler might
+// return or wrap.
+type timeouter interface{ Timeout() bool }
+
+// classifyError inspects err for one of the SDK's typed errors
+// (entities.DNSError, entities.HTTPError, entities.TCPError,
+// entities.NetworkError, entities.CapabilityError, entities.ConfigError,
+// entities.ExecError, entities.ValidationError, entities.KeyValueError, or
+// anything satisfying timeouter) and returns the *entities.ErrorDetail it
+// maps to, with Type and Code as distinct fields. The Type categories follow
+// sdk.ToErrorDetail's for its own typed errors, except that a DNS, HTTP or
+// TCP error keeps Type "network" here even when it timed out; the Codes for
+// network, capability and config errors differ. It is built from this
+// module's own typed-error set since sdk lives in a separate module this
+// package doesn't otherwise depend on. Returns nil when err doesn't match
+// any known category, so the caller can fall back to its own default type.
+func classifyError(err error) *entities.ErrorDetail {
+	var dnsErr *entities.DNSError
+	if errors.As(err, &dnsErr) {
+		return &entities.ErrorDetail{Type: "network", Code: "dns_" + dnsErr.RecordType, Message: err.Error()}
+	}
+
+	var httpErr *entities.HTTPError
+	if errors.As(err, &httpErr) {
+		return &entities.ErrorDetail{Type: "network", Code: fmt.Sprintf("http_%d", httpErr.StatusCode), Message: err.Error()}
+	}
+
+	var tcpErr *entities.TCPError
+	if errors.As(err, &tcpErr) {
+		return &entities.ErrorDetail{Type: "network", Code: "tcp_connect", Message: err.Error()}
+	}
+
+	var netErr *entities.NetworkError
+	if errors.As(err, &netErr) {
+		return &entities.ErrorDetail{Type: "network", Code: "NETWORK_FAILED", Message: err.Error()}
+	}
+
+	var capErr *entities.CapabilityError
+	if errors.As(err, &capErr) {
+		return &entities.ErrorDetail{Type: "capability", Code: "CAPABILITY_DENIED", Message: err.Error()}
+	}
+
+	var cfgErr *entities.ConfigError
+	if errors.As(err, &cfgErr) {
+		return &entities.ErrorDetail{Type: "config", Code: "CONFIG_INVALID", Message: err.Error()}
+	}
+
+	var execErr *entities.ExecError
+	if errors.As(err, &execErr) {
+		return &entities.ErrorDetail{Type: "exec", Code: fmt.Sprintf("exit_%d", execErr.ExitCode), Message: err.Error()}
+	}
+
+	var valErr *entities.ValidationError
+	if errors.As(err, &valErr) {
+		return &entities.ErrorDetail{Type: "validation", Code: "schema", Message: err.Error()}
+	}
+
+	var kvErr *entities.KeyValueError
+	if errors.As(err, &kvErr) {
+		return &entities.ErrorDetail{Type: "capability", Code: "keyvalue", Message: err.Error()}
+	}
+
+	var to timeouter
+	if errors.As(err, &to) && to.Timeout() {
+		return &entities.ErrorDetail{Type: "timeout", Code: "TIMEOUT", Message: err.Error()}
+	}
+
+	return nil
+}
+
+// resultFromError converts a handler-returned error into an *entities.Result
+// with status Error, routing it through classifyError so the host can
+// distinguish timeouts, capability denials, and typed network/exec/
+// validation faults from true execution faults -- the same distinction
+// entities.ErrorDetail already promises. defaultType is used, with no code,
+// when err doesn't match any known category.
+func resultFromError(err error, defaultType string) *entities.Result {
+	detail := classifyError(err)
+	if detail == nil {
+		detail = &entities.ErrorDetail{Type: defaultType, Message: err.Error()}
+	}
+	r := entities.ResultError(detail)
+	return &r
+}
@@ -0,0 +1,95 @@
+package plugin_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/application/plugin"
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errInput struct {
+	Fail string `json:"fail"`
+}
+
+type errOutput struct {
+	OK bool `json:"ok"`
+}
+
+// ErrService's single operation returns whatever error errInput.Fail selects,
+// so one service can exercise every classification in classifyError.
+type ErrService struct {
+	plugin.Service `name:"err_service" desc:"Typed error classification"`
+	Trigger        plugin.Op[errInput, errOutput] `desc:"Returns a chosen error kind" method:"TriggerHandler"`
+}
+
+func (s *ErrService) TriggerHandler(ctx context.Context, in *errInput) (*errOutput, error) {
+	switch in.Fail {
+	case "timeout":
+		return nil, &entities.TimeoutError{Operation: "dial", Err: errors.New("deadline exceeded")}
+	case "capability":
+		return nil, &entities.CapabilityError{Required: "network.egress"}
+	case "network":
+		return nil, &entities.NetworkError{Operation: "dial", Err: errors.New("connection reset")}
+	case "config":
+		return nil, &entities.ConfigError{Field: "hostname", Err: errors.New("must not be empty")}
+	case "dns":
+		return nil, &entities.DNSError{Hostname: "example.invalid", RecordType: "A", Err: errors.New("no such host")}
+	case "http":
+		return nil, &entities.HTTPError{Method: "GET", URL: "https://example.invalid", StatusCode: 503, Err: errors.New("unavailable")}
+	case "tcp":
+		return nil, &entities.TCPError{Address: "10.0.0.1:443", Err: errors.New("connection refused")}
+	case "exec":
+		return nil, &entities.ExecError{Command: "id", ExitCode: 127}
+	case "validation":
+		return nil, &entities.ValidationError{Field: "url", Err: errors.New("must be https")}
+	case "keyvalue":
+		return nil, &entities.KeyValueError{Key: "secret-token", Err: errors.New("not granted")}
+	case "":
+		return &errOutput{OK: true}, nil
+	default:
+		return nil, errors.New("boom")
+	}
+}
+
+func TestTypedHandler_ErrorClassification(t *testing.T) {
+	plugin.RegisterOp[errInput, errOutput]("Trigger")
+
+	def := plugin.DefinePlugin(plugin.PluginDef{Name: "err-plugin", Version: "1.0.0"})
+	require.NoError(t, plugin.RegisterService(def, &ErrService{}))
+
+	handler, ok := def.GetHandler("err_service", "trigger")
+	require.True(t, ok)
+
+	cases := []struct {
+		fail     string
+		wantType string
+		wantCode string
+	}{
+		{"timeout", "timeout", "TIMEOUT"},
+		{"capability", "capability", "CAPABILITY_DENIED"},
+		{"network", "network", "NETWORK_FAILED"},
+		{"config", "config", "CONFIG_INVALID"},
+		{"dns", "network", "dns_A"},
+		{"http", "network", "http_503"},
+		{"tcp", "network", "tcp_connect"},
+		{"exec", "exec", "exit_127"},
+		{"validation", "validation", "schema"},
+		{"keyvalue", "capability", "keyvalue"},
+		{"unknown", "execution", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.fail, func(t *testing.T) {
+			req := &plugin.Request{Raw: []byte(`{"fail": "` + tc.fail + `"}`)}
+			res, err := handler(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, entities.ResultStatusError, res.Status)
+			assert.Equal(t, tc.wantType, res.Error.Type)
+			assert.Equal(t, tc.wantCode, res.Error.Code)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+)
+
+// RunExamples turns every registered operation's Example values into
+// executable conformance tests: each example is executed through its
+// wrapped handler via GetHandler and asserted against ExpectedOutput or
+// ExpectedError, the same way GenerateExampleTests does. Unlike
+// GenerateExampleTests, a successful example's actual output is compared
+// against ExpectedOutput with a full two-way, JSON-normalized deep-equal
+// (missing and unexpected fields both fail the test, not just mismatched
+// ones), and every op's examples are additionally written to a golden file
+// at testdata/examples/<service>/<op>.json as request/response pairs, so a
+// host can replay them as smoke tests against a compiled .wasm instead of
+// re-deriving them from source.
+//
+// clientFactory is called once per operation (not once per example, unlike
+// ExampleTestConfig.MockClientFactory) to build the Request.Client that
+// operation's examples run against.
+func RunExamples(t *testing.T, p *PluginDefinition, clientFactory func(opName string) any) {
+	t.Helper()
+
+	manifest := p.Manifest()
+
+	for svcName, svc := range manifest.Services {
+		svcName, svc := svcName, svc
+		for _, op := range svc.Operations {
+			op := op
+			if len(op.Examples) == 0 {
+				continue
+			}
+
+			client := clientFactory(op.Name)
+			replays := make([]exampleReplay, 0, len(op.Examples))
+
+			for _, ex := range op.Examples {
+				ex := ex
+				t.Run(fmt.Sprintf("%s/%s/%s", svcName, op.Name, ex.Name), func(t *testing.T) {
+					t.Helper()
+					runConformanceExample(t, p, svcName, op.Name, ex, client, func(r exampleReplay) {
+						replays = append(replays, r)
+					})
+				})
+			}
+
+			if err := writeReplayGolden(svcName, op.Name, replays); err != nil {
+				t.Errorf("writing replay golden for %s/%s: %v", svcName, op.Name, err)
+			}
+		}
+	}
+}
+
+// exampleReplay captures one example's request and the handler's actual
+// response for a testdata/examples/<service>/<op>.json replay file.
+type exampleReplay struct {
+	Example string          `json:"example"`
+	Request json.RawMessage `json:"request"`
+	Status  string          `json:"status,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// runConformanceExample executes one example through its handler, passes
+// the replay pair for the op's golden file to record, and then asserts it
+// against ExpectedOutput/ExpectedError. Recording happens before the
+// assertions, so the pair is captured regardless of whether they pass (even
+// when one stops the subtest with t.Fatal) - a failing example's actual
+// behavior is exactly what the golden file should capture until the failure
+// is fixed.
+func runConformanceExample(
+	t *testing.T,
+	p *PluginDefinition,
+	svcName, opName string,
+	ex entities.OperationExample,
+	client any,
+	record func(exampleReplay),
+) {
+	t.Helper()
+
+	handler, ok := p.GetHandler(svcName, opName)
+	if !ok {
+		t.Fatalf("handler not found: %s/%s", svcName, opName)
+	}
+
+	req := &Request{Client: client, Raw: ex.Input}
+	result, err := handler(context.Background(), req)
+	record(newExampleReplay(ex, result))
+
+	if ex.ExpectedError != "" {
+		assertConformanceError(t, ex, result, err)
+	} else {
+		assertConformanceSuccess(t, ex, result, err)
+	}
+}
+
+// assertConformanceSuccess requires a non-error result and, when
+// ExpectedOutput is set, a two-way deep-equal match against it.
+func assertConformanceSuccess(t *testing.T, ex entities.OperationExample, result *entities.Result, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("result is nil")
+	}
+	if result.Status == entities.ResultStatusError {
+		msg := ""
+		if result.Error != nil {
+			msg = result.Error.Message
+		}
+		t.Fatalf("unexpected error result: %s", msg)
+	}
+	if len(ex.ExpectedOutput) == 0 {
+		return
+	}
+
+	var expected map[string]any
+	if err := json.Unmarshal(ex.ExpectedOutput, &expected); err != nil {
+		t.Fatalf("failed to parse expected output: %v", err)
+	}
+	actual := cloneJSON(result.Data)
+	verifySnapshot(t, expected, actual)
+	// verifySnapshot is two-way only at the top level; catch nested
+	// missing or unexpected fields with a full comparison.
+	if !t.Failed() && !reflect.DeepEqual(expected, actual) {
+		t.Errorf("output mismatch in nested fields: expected %v, got %v", expected, actual)
+	}
+}
+
+// assertConformanceError requires either a returned error or an Error
+// status result whose message contains ExpectedError.
+func assertConformanceError(t *testing.T, ex entities.OperationExample, result *entities.Result, err error) {
+	t.Helper()
+
+	if err != nil {
+		if !strings.Contains(err.Error(), ex.ExpectedError) {
+			t.Errorf("expected error containing %q, got %q", ex.ExpectedError, err.Error())
+		}
+		return
+	}
+	if result != nil && result.Status == entities.ResultStatusError && result.Error != nil &&
+		strings.Contains(result.Error.Message, ex.ExpectedError) {
+		return
+	}
+
+	got := "success"
+	if result != nil && result.Error != nil {
+		got = result.Error.Message
+	}
+	t.Errorf("expected error containing %q, got %q", ex.ExpectedError, got)
+}
+
+// newExampleReplay builds the request/response pair runConformanceExample
+// records for a golden file, independent of whether the example's
+// assertions passed.
+func newExampleReplay(ex entities.OperationExample, result *entities.Result) exampleReplay {
+	replay := exampleReplay{Example: ex.Name, Request: ex.Input}
+	if result == nil {
+		return replay
+	}
+
+	replay.Status = fmt.Sprint(result.Status)
+	if result.Status == entities.ResultStatusError {
+		if result.Error != nil {
+			replay.Error = result.Error.Message
+		}
+		return replay
+	}
+	if data, err := json.Marshal(result.Data); err == nil {
+		replay.Data = data
+	}
+	return replay
+}
+
+// writeReplayGolden (re)writes the per-operation replay file, overwriting
+// whatever was there before - unlike the snapshot golden files used
+// elsewhere in this package, it's a recording of the examples' current
+// actual behavior, not something later runs are compared against.
+func writeReplayGolden(svcName, opName string, replays []exampleReplay) error {
+	path := filepath.Join("testdata", "examples", svcName, opName+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	encoded, err := json.MarshalIndent(replays, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(encoded, '\n'), 0o644)
+}
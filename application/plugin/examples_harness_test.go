@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+	"github.com/stretchr/testify/require"
+)
+
+type harnessEchoRequest struct {
+	Message string `json:"message"`
+}
+
+type harnessEchoResponse struct {
+	Reply string `json:"reply"`
+}
+
+type harnessEchoService struct {
+	Service `name:"harness_service" desc:"Harness Service"`
+	EchoOp  Op[harnessEchoRequest, harnessEchoResponse] `desc:"Echoes the message back" method:"Echo"`
+}
+
+func (s *harnessEchoService) Echo(ctx context.Context, req *harnessEchoRequest) (*harnessEchoResponse, error) {
+	if req.Message == "fail" {
+		return nil, os.ErrInvalid
+	}
+	return &harnessEchoResponse{Reply: req.Message}, nil
+}
+
+func newHarnessPlugin(t *testing.T) *PluginDefinition {
+	t.Helper()
+
+	RegisterOp[harnessEchoRequest, harnessEchoResponse]("EchoOp", Example[harnessEchoRequest, harnessEchoResponse]{
+		Name:           "basic",
+		Input:          harnessEchoRequest{Message: "hello"},
+		ExpectedOutput: &harnessEchoResponse{Reply: "hello"},
+	}, Example[harnessEchoRequest, harnessEchoResponse]{
+		Name:          "rejects_fail",
+		Input:         harnessEchoRequest{Message: "fail"},
+		ExpectedError: "invalid",
+	})
+
+	def := DefinePlugin(PluginDef{Name: "harness-plugin", Version: "1.0.0"})
+	require.NoError(t, RegisterService(def, &harnessEchoService{}))
+	return def
+}
+
+// chdirTemp points the working directory at a fresh temp dir for the
+// duration of the test, so writeReplayGolden's hard-coded testdata/examples
+// path doesn't write into the real repo tree.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	return dir
+}
+
+func TestRunExamples_WritesReplayGoldenAndAssertsExamples(t *testing.T) {
+	dir := chdirTemp(t)
+	def := newHarnessPlugin(t)
+
+	RunExamples(t, def, func(opName string) any { return nil })
+
+	path := filepath.Join(dir, "testdata", "examples", "harness_service", "echo_op.json")
+	require.FileExists(t, path)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var replays []exampleReplay
+	require.NoError(t, json.Unmarshal(raw, &replays))
+	require.Len(t, replays, 2)
+
+	require.Equal(t, "basic", replays[0].Example)
+	require.Equal(t, fmt.Sprint(entities.ResultStatusSuccess), replays[0].Status)
+	require.Empty(t, replays[0].Error)
+
+	require.Equal(t, "rejects_fail", replays[1].Example)
+	require.NotEmpty(t, replays[1].Error)
+}
+
+func TestWriteReplayGolden_OverwritesPreviousContent(t *testing.T) {
+	dir := chdirTemp(t)
+
+	require.NoError(t, writeReplayGolden("svc", "op", []exampleReplay{{Example: "first"}}))
+	require.NoError(t, writeReplayGolden("svc", "op", []exampleReplay{{Example: "second"}}))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "testdata", "examples", "svc", "op.json"))
+	require.NoError(t, err)
+
+	var replays []exampleReplay
+	require.NoError(t, json.Unmarshal(raw, &replays))
+	require.Len(t, replays, 1)
+	require.Equal(t, "second", replays[0].Example)
+}
@@ -0,0 +1,733 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// FuzzOperation wires a registered operation's handler into Go's native
+// fuzzing engine. Call it from a FuzzXxx function in a plugin's test package:
+//
+//	func FuzzResolve(f *testing.F) {
+//	    plugin.FuzzOperation(f, core.Plugin, "DNS", "Resolve", &mockResolver{})
+//	}
+//
+// The corpus is seeded from the operation's registered Examples and from
+// GenerateExamplesFromSchema's boundary-value inputs; `go test -fuzz` then
+// mutates those seeds. The fuzz target only fails if the handler panics, or
+// returns neither a usable Result nor a typed error - a handler rejecting a
+// malformed input with an error is a passing outcome.
+func FuzzOperation(f *testing.F, plugin *PluginDefinition, svcName, opName string, mockClient any) {
+	f.Helper()
+
+	handler, ok := plugin.GetHandler(svcName, opName)
+	if !ok {
+		f.Fatalf("handler not found: %s/%s", svcName, opName)
+	}
+
+	manifest := plugin.Manifest()
+	svc, ok := manifest.Services[svcName]
+	if !ok {
+		f.Fatalf("service not found in manifest: %s", svcName)
+	}
+
+	var found bool
+	for _, op := range svc.Operations {
+		if op.Name != opName {
+			continue
+		}
+		found = true
+
+		for _, ex := range op.Examples {
+			f.Add(ex.Input)
+		}
+		for _, ex := range GenerateExamplesFromSchema(op) {
+			f.Add(ex.Input)
+		}
+	}
+	if !found {
+		f.Fatalf("operation not found in manifest: %s/%s", svcName, opName)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		req := &Request{Client: mockClient, Raw: data}
+
+		result, err := callHandlerRecovered(t, handler, req)
+		if err != nil {
+			return // a typed error is a valid outcome for malformed input
+		}
+		if result == nil {
+			t.Fatalf("handler returned nil result and nil error for input %s", data)
+		}
+		if result.Status == entities.ResultStatusError && result.Error == nil {
+			t.Fatalf("result status is error but Error is nil for input %s", data)
+		}
+	})
+}
+
+// callHandlerRecovered invokes handler, turning a panic into a test failure
+// instead of crashing the fuzzer.
+func callHandlerRecovered(t *testing.T, handler HandlerFunc, req *Request) (result *entities.Result, err error) {
+	t.Helper()
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("handler panicked on input %s: %v", req.Raw, r)
+		}
+	}()
+
+	return handler(context.Background(), req)
+}
+
+// jsonSchemaNode is the minimal subset of JSON Schema that
+// GenerateExamplesFromSchema understands: object/array/string/number shape,
+// enough to drive boundary-value generation.
+type jsonSchemaNode struct {
+	Properties map[string]jsonSchemaNode `json:"properties"`
+	Items      *jsonSchemaNode           `json:"items"`
+	Type       string                    `json:"type"`
+}
+
+// schemaBoundary names a boundary-value strategy and the field types it
+// applies to.
+type schemaBoundary struct {
+	name  string
+	value func(fieldType string) (any, bool)
+}
+
+var schemaBoundaries = []schemaBoundary{
+	{"empty_string", emptyStringBoundary},
+	{"max_length_string", maxLengthStringBoundary},
+	{"negative_number", negativeNumberBoundary},
+	{"deeply_nested_array", deeplyNestedArrayBoundary},
+}
+
+// GenerateExamplesFromSchema produces boundary-value inputs (empty strings,
+// max-length strings, negative numbers, deeply nested arrays) for an
+// operation, driven by its InputSchema. It complements hand-written Examples
+// with edge cases plugin authors wouldn't think to write by hand. Operations
+// without an InputSchema (or whose schema has no applicable fields for a
+// given boundary) yield no example for that boundary.
+func GenerateExamplesFromSchema(op entities.OperationManifest) []entities.OperationExample {
+	if len(op.InputSchema) == 0 {
+		return nil
+	}
+
+	var root jsonSchemaNode
+	if err := json.Unmarshal(op.InputSchema, &root); err != nil {
+		return nil
+	}
+
+	var examples []entities.OperationExample
+	for _, b := range schemaBoundaries {
+		input := buildBoundaryInput(root, b.value)
+		if input == nil {
+			continue
+		}
+		inputJSON, err := json.Marshal(input)
+		if err != nil {
+			continue
+		}
+		examples = append(examples, entities.OperationExample{
+			Name:        b.name,
+			Description: "boundary value generated from the input schema",
+			Input:       inputJSON,
+		})
+	}
+	return examples
+}
+
+// buildBoundaryInput applies pick to each top-level property of an object
+// schema, returning nil if no property matched (so the caller can skip that
+// boundary entirely rather than emit a no-op example).
+func buildBoundaryInput(root jsonSchemaNode, pick func(fieldType string) (any, bool)) map[string]any {
+	if root.Type != "object" || len(root.Properties) == 0 {
+		return nil
+	}
+
+	out := make(map[string]any)
+	for name, prop := range root.Properties {
+		if v, ok := pick(prop.Type); ok {
+			out[name] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func emptyStringBoundary(fieldType string) (any, bool) {
+	if fieldType != "string" {
+		return nil, false
+	}
+	return "", true
+}
+
+// maxLengthString is long enough to exercise size limits without making
+// generated corpus files unwieldy.
+const maxLengthString = 8192
+
+func maxLengthStringBoundary(fieldType string) (any, bool) {
+	if fieldType != "string" {
+		return nil, false
+	}
+	return strings.Repeat("x", maxLengthString), true
+}
+
+func negativeNumberBoundary(fieldType string) (any, bool) {
+	switch fieldType {
+	case "integer":
+		return -1, true
+	case "number":
+		return -1.5, true
+	default:
+		return nil, false
+	}
+}
+
+// deeplyNestedArrayDepth controls how many levels deep the generated array
+// boundary nests, enough to stress naive recursive decoders.
+const deeplyNestedArrayDepth = 20
+
+func deeplyNestedArrayBoundary(fieldType string) (any, bool) {
+	if fieldType != "array" {
+		return nil, false
+	}
+	var nested any = []any{}
+	for i := 0; i < deeplyNestedArrayDepth; i++ {
+		nested = []any{nested}
+	}
+	return nested, true
+}
+
+// FuzzTestConfig configures GenerateFuzzTests.
+type FuzzTestConfig struct {
+	// Seed drives the deterministic generator, combined with each op's name
+	// so a given (Seed, op) pair always synthesizes the same sequence of
+	// inputs and a failure reproduces.
+	Seed int64
+
+	// Iterations is how many synthesized inputs to run per operation.
+	// Defaults to 50 if zero.
+	Iterations int
+
+	// SkipOps lists "Service/Op" names to skip entirely.
+	SkipOps []string
+
+	// InputMutator, when set, is applied to every generated input before
+	// it's sent to the handler, letting plugin authors layer domain-specific
+	// corpora on top of the schema-driven values.
+	InputMutator func(op string, in []byte) []byte
+}
+
+const defaultFuzzIterations = 50
+
+// recognizedResultErrorTypes is the set of Result.Error.Type values this SDK
+// actually produces: classifyError's typed-error categories, service.go's
+// "execution"/"panic" fallbacks, and the generic buckets a handler may
+// construct directly via entities.ResultErrorPtr.
+var recognizedResultErrorTypes = map[string]bool{
+	"network": true, "timeout": true, "config": true, "capability": true,
+	"exec": true, "execution": true, "panic": true, "validation": true, "internal": true,
+}
+
+// GenerateFuzzTests synthesizes inputs from each registered operation's
+// InputSchema (honoring type, format, length/pattern/range constraints,
+// enum, and oneOf/anyOf branches) and asserts three invariants per call: the
+// handler never panics, a successful Result.Data validates against
+// OutputSchema, and an error outcome carries an ErrorDetail with a
+// recognized Type. Generation is deterministic per (config.Seed, op name),
+// so a failing iteration reproduces from the test name alone.
+//
+// Unlike FuzzOperation, this doesn't hook into go test -fuzz - it runs a
+// fixed, seeded set of iterations as ordinary subtests, so it needs no
+// corpus directory and runs in a normal `go test`.
+func GenerateFuzzTests(t *testing.T, plugin *PluginDefinition, mockClient any, config FuzzTestConfig) {
+	t.Helper()
+
+	iterations := config.Iterations
+	if iterations <= 0 {
+		iterations = defaultFuzzIterations
+	}
+
+	skip := make(map[string]bool, len(config.SkipOps))
+	for _, name := range config.SkipOps {
+		skip[name] = true
+	}
+
+	manifest := plugin.Manifest()
+	for svcName, svc := range manifest.Services {
+		for _, op := range svc.Operations {
+			opKey := svcName + "/" + op.Name
+			if skip[opKey] {
+				continue
+			}
+
+			handler, ok := plugin.GetHandler(svcName, op.Name)
+			if !ok {
+				t.Fatalf("handler not found: %s", opKey)
+			}
+
+			t.Run(opKey, func(t *testing.T) {
+				rng := rand.New(rand.NewSource(fuzzSeedFor(config.Seed, opKey)))
+				schema := parseFuzzSchema(op.InputSchema)
+				outputValidator := compileFuzzOutputSchema(t, op.OutputSchema)
+
+				for i := 0; i < iterations; i++ {
+					input := synthesizeFuzzInput(schema, rng)
+					if config.InputMutator != nil {
+						input = config.InputMutator(opKey, input)
+					}
+
+					t.Run(fmt.Sprintf("iter_%d", i), func(t *testing.T) {
+						req := &Request{Client: mockClient, Raw: input}
+						result, err := callHandlerRecovered(t, handler, req)
+						checkFuzzInvariants(t, outputValidator, result, err)
+					})
+				}
+			})
+		}
+	}
+}
+
+// fuzzSeedFor derives a deterministic per-op seed from config.Seed and the
+// op's name, so every operation gets an independent, reproducible sequence
+// instead of all operations sharing (and perturbing) one generator.
+func fuzzSeedFor(configSeed int64, opKey string) int64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d:%s", configSeed, opKey)
+	return int64(h.Sum64())
+}
+
+// checkFuzzInvariants asserts the three invariants GenerateFuzzTests
+// promises for a single handler invocation.
+func checkFuzzInvariants(t *testing.T, outputValidator *jsonschema.Schema, result *entities.Result, err error) {
+	t.Helper()
+
+	// Invariant 1 (no panic) is enforced by callHandlerRecovered before this
+	// is ever called.
+
+	if err != nil {
+		t.Fatalf("handler returned a raw error instead of a Result.Error: %v", err)
+	}
+	if result == nil {
+		t.Fatalf("handler returned nil result and nil error")
+	}
+
+	switch result.Status {
+	case entities.ResultStatusError, entities.ResultStatusFailure:
+		// Invariant 3: errors are ErrorDetail values with a recognized Type.
+		if result.Error == nil {
+			t.Fatalf("result status is %s but Error is nil", result.Status)
+		}
+		if !recognizedResultErrorTypes[result.Error.Type] {
+			t.Errorf("result error has unrecognized Type %q: %s", result.Error.Type, result.Error.Message)
+		}
+	case entities.ResultStatusSuccess:
+		// Invariant 2: successful Result.Data validates against OutputSchema.
+		if outputValidator == nil {
+			return
+		}
+		encoded, marshalErr := json.Marshal(result.Data)
+		if marshalErr != nil {
+			t.Fatalf("failed to marshal Result.Data for schema validation: %v", marshalErr)
+		}
+		var decoded any
+		if unmarshalErr := json.Unmarshal(encoded, &decoded); unmarshalErr != nil {
+			t.Fatalf("failed to decode Result.Data for schema validation: %v", unmarshalErr)
+		}
+		if valErr := outputValidator.Validate(decoded); valErr != nil {
+			t.Errorf("Result.Data does not satisfy OutputSchema: %v", valErr)
+		}
+	}
+}
+
+// compileFuzzOutputSchema compiles op's OutputSchema once per operation, so
+// GenerateFuzzTests doesn't recompile it on every iteration. Returns nil if
+// the operation has no OutputSchema or it fails to compile - a missing or
+// broken OutputSchema just skips invariant 2 rather than failing every
+// iteration on a documentation gap.
+func compileFuzzOutputSchema(t *testing.T, outputSchema json.RawMessage) *jsonschema.Schema {
+	t.Helper()
+
+	if len(outputSchema) == 0 {
+		return nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("output.json", strings.NewReader(string(outputSchema))); err != nil {
+		return nil
+	}
+	sch, err := compiler.Compile("output.json")
+	if err != nil {
+		return nil
+	}
+	return sch
+}
+
+// fuzzSchemaNode is the subset of JSON Schema GenerateFuzzTests understands:
+// object/array/string/number/integer/boolean shape, string format/length/
+// pattern constraints, numeric bounds, enum, and oneOf/anyOf branches.
+type fuzzSchemaNode struct {
+	Type       string                    `json:"type"`
+	Format     string                    `json:"format"`
+	Pattern    string                    `json:"pattern"`
+	Properties map[string]fuzzSchemaNode `json:"properties"`
+	Required   []string                  `json:"required"`
+	Items      *fuzzSchemaNode           `json:"items"`
+	Enum       []json.RawMessage         `json:"enum"`
+	OneOf      []fuzzSchemaNode          `json:"oneOf"`
+	AnyOf      []fuzzSchemaNode          `json:"anyOf"`
+	MinLength  *int                      `json:"minLength"`
+	MaxLength  *int                      `json:"maxLength"`
+	Minimum    *float64                  `json:"minimum"`
+	Maximum    *float64                  `json:"maximum"`
+}
+
+// parseFuzzSchema decodes an operation's InputSchema, returning the zero
+// node (no properties, so synthesizeFuzzInput produces "{}") if it's absent
+// or malformed.
+func parseFuzzSchema(inputSchema json.RawMessage) fuzzSchemaNode {
+	var root fuzzSchemaNode
+	if len(inputSchema) == 0 {
+		return root
+	}
+	_ = json.Unmarshal(inputSchema, &root)
+	return root
+}
+
+// synthesizeFuzzInput generates one JSON object from schema: every required
+// property is always populated, optional properties are toggled on/off per
+// call, and every generated leaf has a chance of being replaced by a
+// type-appropriate edge case (empty/max-length string, embedded NUL,
+// Unicode grapheme clusters, zero/negative numbers).
+func synthesizeFuzzInput(schema fuzzSchemaNode, rng *rand.Rand) []byte {
+	if schema.Type != "object" || len(schema.Properties) == 0 {
+		return []byte("{}")
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	out := make(map[string]any, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		if !required[name] && rng.Intn(2) == 0 {
+			continue // optional field, toggled off this iteration
+		}
+		out[name] = genFuzzValue(prop, rng, 0)
+	}
+
+	encoded, err := json.Marshal(out)
+	if err != nil {
+		return []byte("{}")
+	}
+	return encoded
+}
+
+// maxFuzzDepth bounds recursion into nested objects/arrays so a
+// self-referential or pathological schema can't recurse forever.
+const maxFuzzDepth = 6
+
+// genFuzzValue generates one value honoring node's constraints, falling
+// back to an edge case for its type with fuzzEdgeCaseChance probability.
+func genFuzzValue(node fuzzSchemaNode, rng *rand.Rand, depth int) any {
+	if len(node.Enum) > 0 {
+		var v any
+		if err := json.Unmarshal(node.Enum[rng.Intn(len(node.Enum))], &v); err == nil {
+			return v
+		}
+	}
+	if len(node.OneOf) > 0 {
+		return genFuzzValue(node.OneOf[rng.Intn(len(node.OneOf))], rng, depth)
+	}
+	if len(node.AnyOf) > 0 {
+		return genFuzzValue(node.AnyOf[rng.Intn(len(node.AnyOf))], rng, depth)
+	}
+
+	switch node.Type {
+	case "string":
+		if edge, ok := fuzzEdgeCaseString(rng); ok {
+			return edge
+		}
+		return genFuzzString(node, rng)
+	case "integer":
+		if edge, ok := fuzzEdgeCaseNumber(rng); ok {
+			return int64(edge)
+		}
+		return int64(genFuzzNumber(node, rng))
+	case "number":
+		if edge, ok := fuzzEdgeCaseNumber(rng); ok {
+			return edge
+		}
+		return genFuzzNumber(node, rng)
+	case "boolean":
+		return rng.Intn(2) == 0
+	case "array":
+		if depth >= maxFuzzDepth || node.Items == nil {
+			return []any{}
+		}
+		n := rng.Intn(3)
+		items := make([]any, n)
+		for i := range items {
+			items[i] = genFuzzValue(*node.Items, rng, depth+1)
+		}
+		return items
+	case "object":
+		if depth >= maxFuzzDepth || len(node.Properties) == 0 {
+			return map[string]any{}
+		}
+		required := make(map[string]bool, len(node.Required))
+		for _, name := range node.Required {
+			required[name] = true
+		}
+		out := make(map[string]any, len(node.Properties))
+		for name, prop := range node.Properties {
+			if !required[name] && rng.Intn(2) == 0 {
+				continue
+			}
+			out[name] = genFuzzValue(prop, rng, depth+1)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// genFuzzString honors Pattern, Format, and MinLength/MaxLength, in that
+// priority order (a pattern already implies a shape no format helper would
+// match).
+func genFuzzString(node fuzzSchemaNode, rng *rand.Rand) string {
+	if node.Pattern != "" {
+		if s, ok := sampleRegex(node.Pattern, rng); ok {
+			return s
+		}
+		// Pattern too complex to sample; fall through to format/length.
+	}
+
+	if s, ok := fuzzFormatString(node.Format, rng); ok {
+		return s
+	}
+
+	minLen := 0
+	if node.MinLength != nil {
+		minLen = *node.MinLength
+	}
+	maxLen := minLen + 16
+	if node.MaxLength != nil {
+		maxLen = *node.MaxLength
+	}
+	if maxLen < minLen {
+		maxLen = minLen
+	}
+
+	length := minLen
+	if maxLen > minLen {
+		length = minLen + rng.Intn(maxLen-minLen+1)
+	}
+	return randASCIIString(rng, length)
+}
+
+// fuzzFormatString produces a value honoring a JSON Schema "format" keyword.
+// Returns ok=false for formats it doesn't recognize.
+func fuzzFormatString(format string, rng *rand.Rand) (string, bool) {
+	switch format {
+	case "email":
+		return fmt.Sprintf("user%d@example.com", rng.Intn(1000)), true
+	case "hostname":
+		return fmt.Sprintf("host-%d.example.com", rng.Intn(1000)), true
+	case "ipv4":
+		return fmt.Sprintf("198.51.100.%d", rng.Intn(256)), true
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", rng.Intn(0xffff)), true
+	case "uri":
+		return fmt.Sprintf("https://example.com/%d", rng.Intn(1000)), true
+	case "date-time":
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		return base.Add(time.Duration(rng.Intn(1_000_000)) * time.Second).Format(time.RFC3339), true
+	default:
+		return "", false
+	}
+}
+
+// genFuzzNumber honors Minimum/Maximum, defaulting to a small signed range.
+func genFuzzNumber(node fuzzSchemaNode, rng *rand.Rand) float64 {
+	min := -1000.0
+	if node.Minimum != nil {
+		min = *node.Minimum
+	}
+	max := 1000.0
+	if node.Maximum != nil {
+		max = *node.Maximum
+	}
+	if max < min {
+		max = min
+	}
+	return min + rng.Float64()*(max-min)
+}
+
+// fuzzEdgeCaseStrings are mixed into string generation deterministically
+// (via the seeded rng) rather than on every call, covering the corpora a
+// plugin author wouldn't think to write by hand.
+var fuzzEdgeCaseStrings = []string{
+	"",                                   // empty string
+	strings.Repeat("x", maxLengthString), // max-length string
+	"a\x00b",                             // embedded NUL
+	"é\U0001F468‍\U0001F469‍\U0001F467‍\U0001F466", // combining accent + family ZWJ sequence
+}
+
+// fuzzEdgeCaseNumbers covers zero, negative, and signed 64-bit boundary
+// values.
+var fuzzEdgeCaseNumbers = []float64{0, -1, -9223372036854775808, 9223372036854775807}
+
+// fuzzEdgeCaseChance is how often genFuzzValue substitutes an edge case
+// for a normally-generated value, high enough that a handful of iterations
+// reliably exercises every edge case without every iteration being one.
+const fuzzEdgeCaseChance = 4 // 1-in-N
+
+func fuzzEdgeCaseString(rng *rand.Rand) (string, bool) {
+	if rng.Intn(fuzzEdgeCaseChance) != 0 {
+		return "", false
+	}
+	return fuzzEdgeCaseStrings[rng.Intn(len(fuzzEdgeCaseStrings))], true
+}
+
+func fuzzEdgeCaseNumber(rng *rand.Rand) (float64, bool) {
+	if rng.Intn(fuzzEdgeCaseChance) != 0 {
+		return 0, false
+	}
+	return fuzzEdgeCaseNumbers[rng.Intn(len(fuzzEdgeCaseNumbers))], true
+}
+
+const randASCIIStringAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func randASCIIString(rng *rand.Rand, length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = randASCIIStringAlphabet[rng.Intn(len(randASCIIStringAlphabet))]
+	}
+	return string(b)
+}
+
+// maxRegexSampleLen and maxRegexSampleRepeat bound how large a sample
+// sampleRegex will build, so a pattern with large bounded repetition
+// (e.g. {1,10000}) can't blow up generation time or memory.
+const maxRegexSampleLen = 256
+const maxRegexSampleRepeat = 8
+
+// sampleRegex generates a string matching pattern using regexp/syntax's
+// parsed AST, picking a single path through alternations and character
+// classes and capping unbounded repetition. Returns ok=false for patterns
+// regexp/syntax can't parse, or whose generated sample would exceed
+// maxRegexSampleLen (a heuristic for "too complex to sample cheaply").
+func sampleRegex(pattern string, rng *rand.Rand) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	if !sampleRegexNode(re, rng, &b, 0) {
+		return "", false
+	}
+	if b.Len() > maxRegexSampleLen {
+		return "", false
+	}
+	return b.String(), true
+}
+
+func sampleRegexNode(re *syntax.Regexp, rng *rand.Rand, b *strings.Builder, depth int) bool {
+	if depth > 32 {
+		return false
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return false
+		}
+		// Rune holds [lo,hi] pairs; pick one pair and a rune within it.
+		pairIdx := rng.Intn(len(re.Rune) / 2)
+		lo, hi := re.Rune[pairIdx*2], re.Rune[pairIdx*2+1]
+		if hi < lo {
+			return false
+		}
+		b.WriteRune(lo + rune(rng.Intn(int(hi-lo+1))))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(randASCIIStringAlphabet[rng.Intn(len(randASCIIStringAlphabet))])
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpEmptyMatch:
+		// Zero-width assertions contribute no characters.
+	case syntax.OpCapture:
+		if len(re.Sub) != 1 {
+			return false
+		}
+		return sampleRegexNode(re.Sub[0], rng, b, depth+1)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !sampleRegexNode(sub, rng, b, depth+1) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		if len(re.Sub) == 0 {
+			return false
+		}
+		return sampleRegexNode(re.Sub[rng.Intn(len(re.Sub))], rng, b, depth+1)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := regexRepeatBounds(re)
+		if max < 0 || max > maxRegexSampleRepeat {
+			max = maxRegexSampleRepeat
+		}
+		if max < min {
+			max = min
+		}
+		n := min
+		if max > min {
+			n = min + rng.Intn(max-min+1)
+		}
+		if len(re.Sub) != 1 {
+			return false
+		}
+		for i := 0; i < n; i++ {
+			if !sampleRegexNode(re.Sub[0], rng, b, depth+1) {
+				return false
+			}
+		}
+	default:
+		// OpNoMatch and anything else unrecognized: too complex to sample.
+		return false
+	}
+	return true
+}
+
+// regexRepeatBounds returns op's repetition bounds, normalizing Star/Plus/
+// Quest (which don't set Min/Max) to their fixed semantics. A Max of -1
+// means unbounded.
+func regexRepeatBounds(re *syntax.Regexp) (min, max int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, -1
+	case syntax.OpPlus:
+		return 1, -1
+	case syntax.OpQuest:
+		return 0, 1
+	default: // OpRepeat
+		return re.Min, re.Max
+	}
+}
@@ -0,0 +1,169 @@
+package plugin
+
+import (
+	"encoding/json"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+)
+
+func TestGenerateExamplesFromSchema_NoSchemaYieldsNoExamples(t *testing.T) {
+	examples := GenerateExamplesFromSchema(entities.OperationManifest{Name: "op"})
+	assert.Empty(t, examples)
+}
+
+func TestGenerateExamplesFromSchema_BoundaryValuesMatchSchemaTypes(t *testing.T) {
+	op := entities.OperationManifest{
+		Name: "op",
+		InputSchema: []byte(`{
+			"type": "object",
+			"properties": {
+				"host": {"type": "string"},
+				"port": {"type": "integer"},
+				"tags": {"type": "array"}
+			}
+		}`),
+	}
+
+	examples := GenerateExamplesFromSchema(op)
+
+	names := make(map[string]entities.OperationExample, len(examples))
+	for _, ex := range examples {
+		names[ex.Name] = ex
+	}
+
+	require.Contains(t, names, "empty_string")
+	assert.JSONEq(t, `{"host":""}`, string(names["empty_string"].Input))
+
+	require.Contains(t, names, "max_length_string")
+	var maxLen struct {
+		Host string `json:"host"`
+	}
+	require.NoError(t, json.Unmarshal(names["max_length_string"].Input, &maxLen))
+	assert.Len(t, maxLen.Host, maxLengthString)
+
+	require.Contains(t, names, "negative_number")
+	assert.JSONEq(t, `{"port":-1}`, string(names["negative_number"].Input))
+
+	require.Contains(t, names, "deeply_nested_array")
+	assert.Contains(t, string(names["deeply_nested_array"].Input), `"tags"`)
+}
+
+func TestGenerateExamplesFromSchema_SkipsBoundariesWithNoMatchingField(t *testing.T) {
+	op := entities.OperationManifest{
+		Name:        "op",
+		InputSchema: []byte(`{"type": "object", "properties": {"count": {"type": "integer"}}}`),
+	}
+
+	examples := GenerateExamplesFromSchema(op)
+
+	for _, ex := range examples {
+		assert.NotEqual(t, "deeply_nested_array", ex.Name)
+	}
+}
+
+func TestSynthesizeFuzzInput_RequiredFieldsAlwaysPresent(t *testing.T) {
+	schema := parseFuzzSchema([]byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 4},
+			"nickname": {"type": "string"}
+		}
+	}`))
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(synthesizeFuzzInput(schema, rng), &decoded))
+		require.Contains(t, decoded, "name")
+	}
+}
+
+func TestSynthesizeFuzzInput_NoSchemaYieldsEmptyObject(t *testing.T) {
+	schema := parseFuzzSchema(nil)
+	rng := rand.New(rand.NewSource(1))
+	assert.JSONEq(t, `{}`, string(synthesizeFuzzInput(schema, rng)))
+}
+
+func TestGenFuzzValue_EnumOnlyPicksListedValues(t *testing.T) {
+	node := fuzzSchemaNode{Enum: []json.RawMessage{
+		json.RawMessage(`"a"`), json.RawMessage(`"b"`),
+	}}
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		v := genFuzzValue(node, rng, 0)
+		assert.Contains(t, []any{"a", "b"}, v)
+	}
+}
+
+func TestGenFuzzValue_NumberRespectsMinMax(t *testing.T) {
+	min, max := 5.0, 6.0
+	node := fuzzSchemaNode{Type: "number", Minimum: &min, Maximum: &max}
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		v := genFuzzNumber(node, rng)
+		assert.GreaterOrEqual(t, v, min)
+		assert.LessOrEqual(t, v, max)
+	}
+}
+
+func TestFuzzFormatString_RecognizedFormats(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for _, format := range []string{"email", "hostname", "ipv4", "ipv6", "uri", "date-time"} {
+		s, ok := fuzzFormatString(format, rng)
+		assert.True(t, ok, "format %q", format)
+		assert.NotEmpty(t, s)
+	}
+
+	_, ok := fuzzFormatString("unknown-format", rng)
+	assert.False(t, ok)
+}
+
+func TestSampleRegex_GeneratesMatchingStrings(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	pattern := `^[a-z]{3,5}-[0-9]{2}$`
+	re := regexp.MustCompile(pattern)
+
+	for i := 0; i < 50; i++ {
+		s, ok := sampleRegex(pattern, rng)
+		require.True(t, ok)
+		assert.True(t, re.MatchString(s), "generated %q does not match %q", s, pattern)
+	}
+}
+
+func TestSampleRegex_InvalidPatternFails(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	_, ok := sampleRegex("(", rng)
+	assert.False(t, ok)
+}
+
+func TestFuzzSeedFor_DeterministicPerOp(t *testing.T) {
+	a := fuzzSeedFor(42, "Svc/Op")
+	b := fuzzSeedFor(42, "Svc/Op")
+	assert.Equal(t, a, b)
+
+	c := fuzzSeedFor(42, "Svc/OtherOp")
+	assert.NotEqual(t, a, c)
+}
+
+func TestCheckFuzzInvariants_RecognizedErrorTypePasses(t *testing.T) {
+	result := entities.ResultErrorPtr("capability", "denied")
+	checkFuzzInvariants(t, nil, result, nil)
+}
+
+func TestCheckFuzzInvariants_SuccessValidatesAgainstOutputSchema(t *testing.T) {
+	op := entities.OperationManifest{
+		OutputSchema: []byte(`{"type": "object", "required": ["ok"], "properties": {"ok": {"type": "boolean"}}}`),
+	}
+	validator := compileFuzzOutputSchema(t, op.OutputSchema)
+	require.NotNil(t, validator)
+
+	result := entities.ResultSuccessPtr("ok", map[string]any{"ok": true})
+	checkFuzzInvariants(t, validator, result, nil)
+}
@@ -0,0 +1,104 @@
+package plugin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/application/plugin/pluginevents"
+	"github.com/reglet-dev/reglet-sdk/application/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycleInput struct {
+	Fail string `json:"fail"`
+}
+
+type lifecycleOutput struct {
+	OK bool `json:"ok"`
+}
+
+type LifecycleService struct {
+	plugin.Service `name:"lifecycle" desc:"Lifecycle event coverage"`
+	Trigger        plugin.Op[lifecycleInput, lifecycleOutput] `desc:"Triggers a chosen outcome" method:"TriggerHandler"`
+}
+
+func (s *LifecycleService) TriggerHandler(ctx context.Context, in *lifecycleInput) (*lifecycleOutput, error) {
+	if in.Fail == "panic" {
+		panic("boom")
+	}
+	return &lifecycleOutput{OK: true}, nil
+}
+
+type recordingEventSink struct {
+	events []pluginevents.Event
+}
+
+func (r *recordingEventSink) Emit(_ context.Context, ev pluginevents.Event) {
+	r.events = append(r.events, ev)
+}
+
+func (r *recordingEventSink) kinds() []pluginevents.Kind {
+	kinds := make([]pluginevents.Kind, len(r.events))
+	for i, ev := range r.events {
+		kinds[i] = ev.Kind
+	}
+	return kinds
+}
+
+func TestTypedHandler_EmitsLifecycleEvents_Success(t *testing.T) {
+	plugin.RegisterOp[lifecycleInput, lifecycleOutput]("Trigger")
+
+	rec := &recordingEventSink{}
+	pluginevents.SetSink(rec)
+	defer pluginevents.SetSink(nil)
+
+	def := plugin.DefinePlugin(plugin.PluginDef{Name: "lifecycle-plugin", Version: "1.0.0"})
+	require.NoError(t, plugin.RegisterService(def, &LifecycleService{}))
+
+	handler, ok := def.GetHandler("lifecycle", "trigger")
+	require.True(t, ok)
+
+	_, err := handler(context.Background(), &plugin.Request{Raw: []byte(`{}`)})
+	require.NoError(t, err)
+
+	assert.Contains(t, rec.kinds(), pluginevents.KindRegistered)
+	assert.Contains(t, rec.kinds(), pluginevents.KindConfigParsed)
+	assert.Contains(t, rec.kinds(), pluginevents.KindHandlerStarted)
+	assert.Contains(t, rec.kinds(), pluginevents.KindHandlerFinished)
+}
+
+func TestTypedHandler_EmitsPanicRecovered(t *testing.T) {
+	plugin.RegisterOp[lifecycleInput, lifecycleOutput]("Trigger")
+
+	rec := &recordingEventSink{}
+	pluginevents.SetSink(rec)
+	defer pluginevents.SetSink(nil)
+
+	def := plugin.DefinePlugin(plugin.PluginDef{Name: "lifecycle-plugin", Version: "1.0.0"})
+	require.NoError(t, plugin.RegisterService(def, &LifecycleService{}))
+
+	handler, ok := def.GetHandler("lifecycle", "trigger")
+	require.True(t, ok)
+
+	res, err := handler(context.Background(), &plugin.Request{Raw: []byte(`{"fail": "panic"}`)})
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, "panic", res.Error.Type)
+	assert.Contains(t, rec.kinds(), pluginevents.KindPanicRecovered)
+}
+
+func TestPluginEvents_HandlerCanEmitCustomDomainEvent(t *testing.T) {
+	rec := &recordingEventSink{}
+	pluginevents.SetSink(rec)
+	defer pluginevents.SetSink(nil)
+
+	pluginevents.Emit(context.Background(), pluginevents.Event{
+		Kind:   "cert_expiring_soon",
+		Plugin: "tls-check",
+		Attrs:  map[string]any{"days_remaining": 3},
+	})
+
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, pluginevents.Kind("cert_expiring_soon"), rec.events[0].Kind)
+}
@@ -0,0 +1,90 @@
+package plugin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// loggerKey is the context key for storing the per-invocation logger.
+type loggerKey struct{}
+
+// invocationIDKey is the context key for storing the per-invocation ID, so
+// it can be reused as the RequestID on pluginevents.Event without threading
+// it through every call site alongside the logger.
+type invocationIDKey struct{}
+
+// LoggerFrom returns the per-invocation logger attached to ctx by the SDK
+// before a handler is called. Every record emitted through it is
+// automatically tagged with plugin, service, operation, and invocation_id
+// attributes, so host-side logs correlate back to the call that produced
+// them without any boilerplate in the handler itself:
+//
+//	func (s *DNSService) ResolveHandler(ctx context.Context, in *ResolveInput) (*ResolveOutput, error) {
+//	    plugin.LoggerFrom(ctx).Info("resolving", "hostname", in.Hostname)
+//	    ...
+//	}
+//
+// Outside of a handler invocation (e.g. code that never went through
+// wrapTypedMethod/wrapLegacyMethod), it falls back to slog.Default().
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// withInvocationLogger builds the per-invocation logger for a single
+// handler call, wrapping whatever handler slog is currently configured with
+// (on a WASM guest, the host-transport handler installed at init time) with
+// correlation attributes, and returns a context carrying it alongside the
+// logger itself so callers can also stash it on Request.Logger.
+func withInvocationLogger(ctx context.Context, pluginName, svcName, opName string) (context.Context, *slog.Logger) {
+	id := newInvocationID()
+	logger := slog.New(slog.Default().Handler().WithAttrs([]slog.Attr{
+		slog.String("plugin", pluginName),
+		slog.String("service", svcName),
+		slog.String("operation", opName),
+		slog.String("invocation_id", id),
+	}))
+	ctx = context.WithValue(ctx, loggerKey{}, logger)
+	ctx = context.WithValue(ctx, invocationIDKey{}, id)
+	return ctx, logger
+}
+
+// RequestIDFrom returns the invocation ID withInvocationLogger attached to
+// ctx (the same value logged as invocation_id), or "" outside of a handler
+// invocation. Used to tag pluginevents.Event.RequestID.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(invocationIDKey{}).(string)
+	return id
+}
+
+// flushable is satisfied by a slog.Handler that batches records and needs
+// an explicit flush at invocation boundaries, such as go/log's
+// WasmLogHandler on the WASM guest. Checked structurally so this package
+// carries no dependency on any particular logging transport.
+type flushable interface{ Flush() }
+
+// flushLogger flushes logger's handler if it implements flushable. The
+// typed and legacy method wrappers defer this immediately after attaching
+// the per-invocation logger, so it also runs on panic recovery.
+func flushLogger(logger *slog.Logger) {
+	if logger == nil {
+		return
+	}
+	if f, ok := logger.Handler().(flushable); ok {
+		f.Flush()
+	}
+}
+
+// newInvocationID generates a short identifier to correlate every log line
+// emitted during a single handler invocation.
+func newInvocationID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerFrom_FallsBackToDefaultOutsideInvocation(t *testing.T) {
+	assert.Equal(t, slog.Default(), LoggerFrom(context.Background()))
+}
+
+func TestWithInvocationLogger_TagsCorrelationAttrsAndIsRetrievable(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	ctx, logger := withInvocationLogger(context.Background(), "myplugin", "dns", "resolve")
+
+	assert.Same(t, logger, LoggerFrom(ctx))
+
+	logger.Info("did a thing")
+	out := buf.String()
+	assert.Contains(t, out, "plugin=myplugin")
+	assert.Contains(t, out, "service=dns")
+	assert.Contains(t, out, "operation=resolve")
+	assert.Contains(t, out, "invocation_id=")
+}
+
+func TestRequestIDFrom_MatchesLoggedInvocationID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	ctx, logger := withInvocationLogger(context.Background(), "myplugin", "dns", "resolve")
+	assert.NotEmpty(t, RequestIDFrom(ctx))
+
+	logger.Info("did a thing")
+	assert.Contains(t, buf.String(), "invocation_id="+RequestIDFrom(ctx))
+}
+
+func TestRequestIDFrom_EmptyOutsideInvocation(t *testing.T) {
+	assert.Empty(t, RequestIDFrom(context.Background()))
+}
+
+type flushCountingHandler struct {
+	slog.Handler
+	flushes int
+}
+
+func (h *flushCountingHandler) Flush() { h.flushes++ }
+
+func TestFlushLogger_CallsFlushWhenHandlerSupportsIt(t *testing.T) {
+	h := &flushCountingHandler{Handler: slog.NewTextHandler(&bytes.Buffer{}, nil)}
+	flushLogger(slog.New(h))
+	assert.Equal(t, 1, h.flushes)
+}
+
+func TestFlushLogger_NoopForNonFlushableHandlerOrNilLogger(t *testing.T) {
+	assert.NotPanics(t, func() {
+		flushLogger(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+		flushLogger(nil)
+	})
+}
+
+func TestNewInvocationID_IsNonEmptyAndVaries(t *testing.T) {
+	a := newInvocationID()
+	b := newInvocationID()
+	assert.NotEmpty(t, a)
+	assert.NotEqual(t, a, b)
+}
@@ -0,0 +1,220 @@
+// Package metrics accumulates Prometheus-style counters and latency
+// histograms for plugin handler execution, with no dependency on an actual
+// Prometheus client library. wrapTypedMethod in the plugin package calls
+// RecordHandler once per invocation; Gather renders the accumulated values
+// in Prometheus text exposition format for a host to scrape.
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+)
+
+// defaultBucketsSeconds are the histogram bucket upper bounds for
+// reglet_plugin_handler_duration_seconds, aligned with the probe package's
+// timing buckets (1ms, 5ms, 25ms, 100ms, 500ms, 2.5s, 10s) so a dashboard
+// can compare handler latency against probe latency on the same scale.
+var defaultBucketsSeconds = []float64{0.001, 0.005, 0.025, 0.1, 0.5, 2.5, 10}
+
+// ErrorKind classifies err into the short label value
+// reglet_plugin_handler_executions_total's error_kind carries. It type-
+// switches against the typed errors defined in domain/entities
+// (NetworkError, TimeoutError, CapabilityError, ConfigError, DNSError,
+// HTTPError, TCPError); err == nil reports "" (success). Any other error -
+// including the MemoryError and WireFormatError variants that exist only in
+// the separate guest-facing SDK module (go/errors.go), which this module
+// can't import - falls back to "error".
+func ErrorKind(err error) string {
+	if err == nil {
+		return ""
+	}
+	switch err.(type) {
+	case *entities.NetworkError:
+		return "network"
+	case *entities.TimeoutError:
+		return "timeout"
+	case *entities.CapabilityError:
+		return "capability"
+	case *entities.ConfigError:
+		return "config"
+	case *entities.DNSError:
+		return "dns"
+	case *entities.HTTPError:
+		return "http"
+	case *entities.TCPError:
+		return "tcp"
+	default:
+		return "error"
+	}
+}
+
+// handlerKey identifies one reglet_plugin_handler_executions_total series.
+type handlerKey struct {
+	plugin    string
+	service   string
+	op        string
+	errorKind string
+}
+
+// histogramKey identifies one reglet_plugin_handler_duration_seconds series.
+// Unlike handlerKey, it's not split by error_kind - bucket counts would get
+// too sparse per error kind to be useful, and duration is what the buckets
+// are for.
+type histogramKey struct {
+	plugin  string
+	service string
+	op      string
+}
+
+// histogram accumulates per-bucket counts and a running sum, the same shape
+// a Prometheus client-side histogram tracks internally.
+type histogram struct {
+	bucketCounts []int64 // parallel to defaultBucketsSeconds, cumulative computed at render time
+	sum          float64
+	count        int64
+}
+
+// Registry accumulates handler and ABI-event metrics. The zero value isn't
+// ready to use; call New.
+type Registry struct {
+	mu             sync.Mutex
+	executionCount map[handlerKey]int64
+	durations      map[histogramKey]*histogram
+	abiEventCount  map[string]int64
+}
+
+// New returns an empty Registry ready to record executions and be scraped
+// via Gather.
+func New() *Registry {
+	return &Registry{
+		executionCount: make(map[handlerKey]int64),
+		durations:      make(map[histogramKey]*histogram),
+		abiEventCount:  make(map[string]int64),
+	}
+}
+
+// defaultRegistry is what RecordHandler and RecordABIEvent use unless a
+// caller wants an isolated Registry (e.g. in a test). There's one plugin
+// process per wasm instance, so a single package-level registry is simpler
+// than threading one through every call site.
+var defaultRegistry = New()
+
+// Default returns the package-level Registry that RecordHandler and
+// RecordABIEvent record to, and that __metrics_scrape (once wired up in the
+// module that owns the wasm export surface - see Gather's doc comment)
+// would scrape.
+func Default() *Registry { return defaultRegistry }
+
+// RecordHandler records one wrapTypedMethod invocation: its plugin/service/
+// op labels, how long it took, and errKind (empty string for a successful
+// call). wrapTypedMethod already classifies its result via classifyError,
+// so callers there should pass result.Error.Type straight through rather
+// than re-deriving it from the original error; ErrorKind exists for
+// callers that only have a raw error to start from.
+func (r *Registry) RecordHandler(pluginName, service, op, errKind string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.executionCount[handlerKey{plugin: pluginName, service: service, op: op, errorKind: errKind}]++
+
+	hk := histogramKey{plugin: pluginName, service: service, op: op}
+	h, ok := r.durations[hk]
+	if !ok {
+		h = &histogram{bucketCounts: make([]int64, len(defaultBucketsSeconds))}
+		r.durations[hk] = h
+	}
+	seconds := duration.Seconds()
+	h.sum += seconds
+	h.count++
+	for i, upper := range defaultBucketsSeconds {
+		if seconds <= upper {
+			h.bucketCounts[i]++
+			break
+		}
+	}
+}
+
+// RecordHandler records to the package-level Default Registry.
+func RecordHandler(pluginName, service, op, errKind string, duration time.Duration) {
+	defaultRegistry.RecordHandler(pluginName, service, op, errKind, duration)
+}
+
+// RecordABIEvent increments the counter for an ABI-level event (e.g.
+// "allocate", "deallocate", "free_all_tracked"). The abi package that
+// actually performs these events lives in a separate module from this one
+// (go/internal/abi) and can't import this package today, so nothing calls
+// this yet; it's exported so that call site can record once the two SDK
+// trees share a module.
+func (r *Registry) RecordABIEvent(kind string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.abiEventCount[kind]++
+}
+
+// RecordABIEvent records to the package-level Default Registry.
+func RecordABIEvent(kind string) {
+	defaultRegistry.RecordABIEvent(kind)
+}
+
+// Gather renders the accumulated counters and histogram in Prometheus text
+// exposition format.
+//
+// This is the data a host-exported "__metrics_scrape" function (per the
+// request this package was added for) would pack with abi.PackPtrLen and
+// return to the host. abi.PackPtrLen lives in go/internal/abi, in the
+// separate module that owns this plugin's wasm export surface (see
+// go/plugin.go's "describe"/"schema"/"observe" exports) - this module
+// doesn't import it and can't declare a //go:wasmexport function against
+// it, so wiring __metrics_scrape itself is follow-up work for whichever
+// module ends up hosting the export once the two SDK trees are unified.
+func (r *Registry) Gather() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP reglet_plugin_handler_executions_total Total typed handler invocations.\n")
+	b.WriteString("# TYPE reglet_plugin_handler_executions_total counter\n")
+	for key, count := range r.executionCount {
+		fmt.Fprintf(&b, "reglet_plugin_handler_executions_total{plugin=%q,service=%q,op=%q,error_kind=%q} %d\n",
+			key.plugin, key.service, key.op, key.errorKind, count)
+	}
+
+	b.WriteString("# HELP reglet_plugin_handler_duration_seconds Time spent executing a typed handler.\n")
+	b.WriteString("# TYPE reglet_plugin_handler_duration_seconds histogram\n")
+	for key, h := range r.durations {
+		var cumulative int64
+		for i, upper := range defaultBucketsSeconds {
+			cumulative += h.bucketCounts[i]
+			fmt.Fprintf(&b, "reglet_plugin_handler_duration_seconds_bucket{plugin=%q,service=%q,op=%q,le=%q} %d\n",
+				key.plugin, key.service, key.op, formatBucketBound(upper), cumulative)
+		}
+		fmt.Fprintf(&b, "reglet_plugin_handler_duration_seconds_bucket{plugin=%q,service=%q,op=%q,le=\"+Inf\"} %d\n",
+			key.plugin, key.service, key.op, h.count)
+		fmt.Fprintf(&b, "reglet_plugin_handler_duration_seconds_sum{plugin=%q,service=%q,op=%q} %v\n",
+			key.plugin, key.service, key.op, h.sum)
+		fmt.Fprintf(&b, "reglet_plugin_handler_duration_seconds_count{plugin=%q,service=%q,op=%q} %d\n",
+			key.plugin, key.service, key.op, h.count)
+	}
+
+	b.WriteString("# HELP reglet_abi_events_total Total ABI-level events (allocate, deallocate, free_all_tracked).\n")
+	b.WriteString("# TYPE reglet_abi_events_total counter\n")
+	for kind, count := range r.abiEventCount {
+		fmt.Fprintf(&b, "reglet_abi_events_total{kind=%q} %d\n", kind, count)
+	}
+
+	return b.String()
+}
+
+// Gather renders the package-level Default Registry.
+func Gather() string { return defaultRegistry.Gather() }
+
+// formatBucketBound renders a bucket upper bound the way Prometheus's own
+// client libraries do for a float64 "le" label value.
+func formatBucketBound(upper float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", upper), "0"), ".")
+}
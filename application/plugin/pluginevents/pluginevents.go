@@ -0,0 +1,183 @@
+// Package pluginevents publishes strongly-typed plugin lifecycle events
+// across the host boundary, modeled on Docker's plugin event stream: other
+// host-side subsystems (schedulers, audit, swarm-style controllers) can
+// subscribe to Kind-tagged Events instead of scraping logs or Evidence data.
+// In-process subscribers use Watch; cross-boundary forwarding uses Sink.
+package pluginevents
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+)
+
+// Kind identifies the phase of an observation an Event describes, or a
+// custom domain event emitted by handler code (e.g. "cert_expiring_soon").
+type Kind string
+
+// Lifecycle kinds. RegisterService and the typed handler wrapper publish
+// all of them automatically except KindEvidenceEmitted, which nothing in
+// the SDK publishes itself.
+const (
+	KindRegistered       Kind = "registered"
+	KindConfigParsed     Kind = "config_parsed"
+	KindHandlerStarted   Kind = "handler_started"
+	KindHandlerFinished  Kind = "handler_finished"
+	KindCapabilityDenied Kind = "capability_denied"
+	KindPanicRecovered   Kind = "panic_recovered"
+	KindEvidenceEmitted  Kind = "evidence_emitted"
+)
+
+// Event describes one phase a plugin goes through during an observation, or
+// a custom domain event a handler publishes via Emit.
+type Event struct {
+	Kind      Kind
+	Timestamp time.Time
+	Plugin    string
+	Service   string
+	Operation string
+	RequestID string
+	Attrs     map[string]any
+	Error     *entities.ErrorDetail
+}
+
+// Sink receives every Event published via Emit. The default Sink discards
+// events, and nothing in this module installs one that forwards to the
+// host: the wasip1-tagged go/pluginevents package has its own Emit and
+// does not call SetSink. Tests and native builds can install their own
+// with SetSink to capture events in-process.
+type Sink interface {
+	Emit(ctx context.Context, ev Event)
+}
+
+// discardSink is the default Sink, used until SetSink installs a real one.
+type discardSink struct{}
+
+func (discardSink) Emit(context.Context, Event) {}
+
+var sink Sink = discardSink{}
+
+// SetSink installs s as the destination for subsequent Emit calls. Passing
+// nil restores the no-op default.
+func SetSink(s Sink) {
+	if s == nil {
+		s = discardSink{}
+	}
+	sink = s
+}
+
+// Emit publishes ev to the installed Sink. Timestamp is set to time.Now()
+// if left zero. Handlers can call this directly to publish their own
+// domain events, e.g.:
+//
+//	pluginevents.Emit(ctx, pluginevents.Event{
+//	    Kind:      "cert_expiring_soon",
+//	    Plugin:    "tls-check",
+//	    Attrs:     map[string]any{"days_remaining": 3},
+//	})
+func Emit(ctx context.Context, ev Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	sink.Emit(ctx, ev)
+	broadcast(ev)
+}
+
+// Filter narrows which Events a Watch channel receives. A zero-valued field
+// matches anything; a Filter with every field left zero matches every
+// event for the watched plugin.
+type Filter struct {
+	// Kinds restricts delivery to these Kinds. Empty means all kinds.
+	Kinds []Kind
+	// Service restricts delivery to events from this service name. Empty
+	// means any service.
+	Service string
+	// Operation restricts delivery to events from this operation name.
+	// Empty means any operation.
+	Operation string
+}
+
+func (f Filter) matches(ev Event) bool {
+	if len(f.Kinds) > 0 {
+		var found bool
+		for _, k := range f.Kinds {
+			if k == ev.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Service != "" && f.Service != ev.Service {
+		return false
+	}
+	if f.Operation != "" && f.Operation != ev.Operation {
+		return false
+	}
+	return true
+}
+
+// watchBuffer bounds how many unread events a watcher channel holds before
+// Emit starts dropping for it, so a slow or abandoned watcher can't block
+// plugin execution.
+const watchBuffer = 32
+
+type watcher struct {
+	plugin string
+	filter Filter
+	ch     chan Event
+}
+
+var (
+	watchMu sync.Mutex
+	watches = make(map[*watcher]struct{})
+)
+
+// Watch subscribes to events published for plugin, returning a channel that
+// receives every Event matching filter until ctx is done, at which point the
+// channel is closed. Unlike Sink (a single destination used to forward
+// events across the host boundary), Watch supports any number of concurrent
+// in-process subscribers - e.g. a test asserting on lifecycle events, or a
+// host-side controller observing a running plugin without polling.
+//
+// A subscriber that falls behind has events silently dropped rather than
+// blocking the plugin; Watch is for observability, not a delivery
+// guarantee.
+func Watch(ctx context.Context, plugin string, filter Filter) <-chan Event {
+	w := &watcher{plugin: plugin, filter: filter, ch: make(chan Event, watchBuffer)}
+
+	watchMu.Lock()
+	watches[w] = struct{}{}
+	watchMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		watchMu.Lock()
+		delete(watches, w)
+		watchMu.Unlock()
+		close(w.ch)
+	}()
+
+	return w.ch
+}
+
+// broadcast fans ev out to every active Watch subscriber for ev.Plugin whose
+// Filter matches, dropping it for subscribers whose channel is full.
+func broadcast(ev Event) {
+	watchMu.Lock()
+	defer watchMu.Unlock()
+
+	for w := range watches {
+		if w.plugin != ev.Plugin || !w.filter.matches(ev) {
+			continue
+		}
+		select {
+		case w.ch <- ev:
+		default:
+		}
+	}
+}
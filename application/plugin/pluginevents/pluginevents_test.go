@@ -0,0 +1,144 @@
+package pluginevents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	events []Event
+}
+
+func (r *recordingSink) Emit(_ context.Context, ev Event) {
+	r.events = append(r.events, ev)
+}
+
+func TestEmit_DefaultSinkIsNoop(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Emit(context.Background(), Event{Kind: KindRegistered})
+	})
+}
+
+func TestEmit_SetSinkReceivesEventsAndStampsTimestamp(t *testing.T) {
+	rec := &recordingSink{}
+	SetSink(rec)
+	defer SetSink(nil)
+
+	Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "p", Service: "s", Operation: "o"})
+
+	if assert.Len(t, rec.events, 1) {
+		ev := rec.events[0]
+		assert.Equal(t, KindHandlerStarted, ev.Kind)
+		assert.False(t, ev.Timestamp.IsZero())
+	}
+}
+
+func TestSetSink_NilRestoresDiscardSink(t *testing.T) {
+	rec := &recordingSink{}
+	SetSink(rec)
+	SetSink(nil)
+
+	Emit(context.Background(), Event{Kind: KindRegistered})
+	assert.Empty(t, rec.events)
+}
+
+func TestWatch_ReceivesMatchingEventsForItsPlugin(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := Watch(ctx, "my-plugin", Filter{})
+
+	Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "my-plugin"})
+	Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "other-plugin"})
+
+	select {
+	case ev := <-ch:
+		assert.Equal(t, "my-plugin", ev.Plugin)
+	case <-time.After(time.Second):
+		t.Fatal("expected an event on the watch channel")
+	}
+
+	select {
+	case ev := <-ch:
+		t.Fatalf("did not expect an event for another plugin, got %+v", ev)
+	default:
+	}
+}
+
+func TestWatch_FilterRestrictsKinds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := Watch(ctx, "my-plugin", Filter{Kinds: []Kind{KindPanicRecovered}})
+
+	Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "my-plugin"})
+	Emit(context.Background(), Event{Kind: KindPanicRecovered, Plugin: "my-plugin"})
+
+	select {
+	case ev := <-ch:
+		assert.Equal(t, KindPanicRecovered, ev.Kind)
+	case <-time.After(time.Second):
+		t.Fatal("expected the panic_recovered event on the watch channel")
+	}
+
+	select {
+	case ev := <-ch:
+		t.Fatalf("did not expect a second event, got %+v", ev)
+	default:
+	}
+}
+
+func TestWatch_FilterRestrictsServiceAndOperation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := Watch(ctx, "my-plugin", Filter{Service: "dns", Operation: "resolve"})
+
+	Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "my-plugin", Service: "http", Operation: "get"})
+	Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "my-plugin", Service: "dns", Operation: "resolve"})
+
+	select {
+	case ev := <-ch:
+		assert.Equal(t, "dns", ev.Service)
+		assert.Equal(t, "resolve", ev.Operation)
+	case <-time.After(time.Second):
+		t.Fatal("expected the dns/resolve event on the watch channel")
+	}
+}
+
+func TestWatch_ClosesChannelWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := Watch(ctx, "my-plugin", Filter{})
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok, "expected the channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("expected the channel to close promptly after cancellation")
+	}
+}
+
+func TestWatch_SlowSubscriberDropsRatherThanBlocksEmit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_ = Watch(ctx, "my-plugin", Filter{}) // never drained
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < watchBuffer*2; i++ {
+			Emit(context.Background(), Event{Kind: KindHandlerStarted, Plugin: "my-plugin"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Emit blocked on a full watch channel")
+	}
+}
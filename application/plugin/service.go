@@ -4,11 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/reglet-dev/reglet-sdk/domain/entities"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/application/plugin/metrics"
+	"github.com/reglet-dev/reglet-plugin-sdk/application/plugin/pluginevents"
 )
 
 // Service is embedded in service structs to provide metadata.
@@ -20,6 +25,11 @@ type Request struct {
 	Client interface{} // Plugin-specific client (e.g., *AWSClient)
 	Config interface{} // Parsed config struct
 	Raw    []byte      // Raw config JSON
+
+	// Logger is the per-invocation logger, tagged with plugin, service,
+	// operation, and invocation_id attributes. Handlers should generally
+	// prefer plugin.LoggerFrom(ctx), which returns the same logger.
+	Logger *slog.Logger
 }
 
 // HandlerFunc is the signature for operation handlers.
@@ -70,10 +80,10 @@ func RegisterService(plugin *PluginDefinition, svc interface{}) error {
 
 		if op.isTyped {
 			// Typed handler: func(ctx, *Input) (*Output, error)
-			handler, wrapErr = wrapTypedMethod(method, op.inputType, op.outputType)
+			handler, wrapErr = wrapTypedMethod(plugin.Name(), serviceName, op.name, method, op.inputType, op.outputType)
 		} else {
 			// Legacy handler: func(ctx, *Request) (*Result, error)
-			handler, wrapErr = wrapLegacyMethod(method)
+			handler, wrapErr = wrapLegacyMethod(plugin.Name(), serviceName, op.name, method)
 		}
 
 		if wrapErr != nil {
@@ -89,6 +99,13 @@ func RegisterService(plugin *PluginDefinition, svc interface{}) error {
 			op.outputType,
 			op.examples,
 		)
+
+		pluginevents.Emit(context.Background(), pluginevents.Event{
+			Kind:      pluginevents.KindRegistered,
+			Plugin:    plugin.Name(),
+			Service:   serviceName,
+			Operation: op.name,
+		})
 	}
 
 	return nil
@@ -186,7 +203,9 @@ func isTypedOp(t reflect.Type) bool {
 
 // wrapTypedMethod wraps a typed handler as HandlerFunc.
 // Expected signature: func(ctx context.Context, in *I) (*O, error)
-func wrapTypedMethod(method reflect.Value, inputType, outputType reflect.Type) (HandlerFunc, error) {
+// pluginName, svcName, and opName tag the per-invocation logger attached to
+// ctx and req.Logger (see withInvocationLogger).
+func wrapTypedMethod(pluginName, svcName, opName string, method reflect.Value, inputType, outputType reflect.Type) (HandlerFunc, error) {
 	methodType := method.Type()
 
 	// Validate signature
@@ -218,49 +237,99 @@ func wrapTypedMethod(method reflect.Value, inputType, outputType reflect.Type) (
 		return nil, fmt.Errorf("second return must be error")
 	}
 
-	return func(ctx context.Context, req *Request) (*entities.Result, error) {
-		// 1. Inject client into context
+	return func(ctx context.Context, req *Request) (result *entities.Result, err error) {
+		// 1. Attach the per-invocation logger to both ctx and req
+		ctx, req.Logger = withInvocationLogger(ctx, pluginName, svcName, opName)
+		defer flushLogger(req.Logger)
+
+		// Record this invocation's outcome once result has its final value
+		// (including any panic recovery below), so the recorded error_kind
+		// always matches what the host actually saw.
+		start := time.Now()
+		defer func() {
+			errKind := ""
+			if result != nil && result.Error != nil {
+				errKind = result.Error.Type
+			}
+			metrics.RecordHandler(pluginName, svcName, opName, errKind, time.Since(start))
+		}()
+
+		emit := func(kind pluginevents.Kind, detail *entities.ErrorDetail) {
+			pluginevents.Emit(ctx, pluginevents.Event{
+				Kind:      kind,
+				Plugin:    pluginName,
+				Service:   svcName,
+				Operation: opName,
+				RequestID: RequestIDFrom(ctx),
+				Error:     detail,
+			})
+		}
+
+		// 2. Inject client into context
 		ctx = WithClient(ctx, req.Client)
 
-		// 2. Parse config JSON into input type
+		// 3. Parse config JSON into input type
 		inputPtr := reflect.New(inputType)
 		if len(req.Raw) > 0 {
 			if err := json.Unmarshal(req.Raw, inputPtr.Interface()); err != nil {
 				return entities.ResultErrorPtr("config", fmt.Sprintf("failed to parse config: %v", err)), nil
 			}
 		}
+		emit(pluginevents.KindConfigParsed, nil)
+
+		// 4. Call the typed handler, recovering from panics so one bad
+		// handler can't take down the whole plugin.
+		emit(pluginevents.KindHandlerStarted, nil)
+		defer func() {
+			if r := recover(); r != nil {
+				detail := entities.NewErrorDetail("panic", fmt.Sprintf("%v", r))
+				emit(pluginevents.KindPanicRecovered, detail)
+				result = entities.ResultErrorPtr("panic", fmt.Sprintf("%v", r))
+				emit(pluginevents.KindHandlerFinished, detail)
+			}
+		}()
 
-		// 3. Call the typed handler
 		args := []reflect.Value{
 			reflect.ValueOf(ctx),
 			inputPtr,
 		}
 		results := method.Call(args)
 
-		// 4. Handle error return
+		// 5. Handle error return
 		if !results[1].IsNil() {
-			err := results[1].Interface().(error)
-			return entities.ResultErrorPtr("execution", err.Error()), nil
+			handlerErr := results[1].Interface().(error)
+			errResult := resultFromError(handlerErr, "execution")
+			if errResult.Error.Type == "capability" {
+				emit(pluginevents.KindCapabilityDenied, errResult.Error)
+			}
+			emit(pluginevents.KindHandlerFinished, errResult.Error)
+			return errResult, nil
 		}
 
-		// 5. Handle nil output
+		// 6. Handle nil output
 		if results[0].IsNil() {
+			emit(pluginevents.KindHandlerFinished, nil)
 			return entities.ResultSuccessPtr("ok", nil), nil
 		}
 
-		// 6. Convert output struct to map[string]any for Result.Data
+		// 7. Convert output struct to map[string]any for Result.Data
 		output := results[0].Interface()
-		data, err := structToMap(output)
-		if err != nil {
-			return entities.ResultErrorPtr("output", fmt.Sprintf("failed to serialize output: %v", err)), nil
+		data, serializeErr := structToMap(output)
+		if serializeErr != nil {
+			errResult := entities.ResultErrorPtr("internal", fmt.Sprintf("failed to serialize output: %v", serializeErr))
+			emit(pluginevents.KindHandlerFinished, errResult.Error)
+			return errResult, nil
 		}
 
+		emit(pluginevents.KindHandlerFinished, nil)
 		return entities.ResultSuccessPtr("ok", data), nil
 	}, nil
 }
 
 // wrapLegacyMethod wraps a legacy handler (existing signature).
-func wrapLegacyMethod(method reflect.Value) (HandlerFunc, error) {
+// pluginName, svcName, and opName tag the per-invocation logger attached to
+// ctx and req.Logger (see withInvocationLogger).
+func wrapLegacyMethod(pluginName, svcName, opName string, method reflect.Value) (HandlerFunc, error) {
 	methodType := method.Type()
 
 	if methodType.NumIn() != 2 || methodType.NumOut() != 2 {
@@ -286,6 +355,9 @@ func wrapLegacyMethod(method reflect.Value) (HandlerFunc, error) {
 	}
 
 	return func(ctx context.Context, req *Request) (*entities.Result, error) {
+		ctx, req.Logger = withInvocationLogger(ctx, pluginName, svcName, opName)
+		defer flushLogger(req.Logger)
+
 		args := []reflect.Value{
 			reflect.ValueOf(ctx),
 			reflect.ValueOf(req),
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -31,7 +34,7 @@ func GenerateExampleTests(t *testing.T, plugin *PluginDefinition, mockClient any
 				testName := fmt.Sprintf("%s/%s/%s", svcName, op.Name, ex.Name)
 
 				t.Run(testName, func(t *testing.T) {
-					runExampleTest(t, plugin, svcName, op.Name, ex, mockClient)
+					runExampleTest(t, plugin, svcName, op.Name, ex, mockClient, ExampleTestConfig{})
 				})
 			}
 		}
@@ -45,6 +48,7 @@ func runExampleTest(
 	svcName, opName string,
 	ex entities.OperationExample,
 	mockClient any,
+	cfg ExampleTestConfig,
 ) {
 	t.Helper()
 
@@ -100,6 +104,204 @@ func runExampleTest(
 		}
 
 		verifyOutput(t, expected, result.Data)
+		return
+	}
+
+	// No expected output given: fall back to snapshot comparison if configured.
+	if cfg.SnapshotDir != "" || os.Getenv("UPDATE_SNAPSHOTS") == "1" {
+		runSnapshotTest(t, cfg, svcName, opName, ex.Name, result.Data)
+	}
+}
+
+// runSnapshotTest compares (or records) actual against a golden file under
+// cfg.SnapshotDir, after applying cfg.Redactors to scrub volatile fields and
+// cfg.Matchers to loosely verify specific JSON paths instead of requiring
+// an exact match.
+func runSnapshotTest(t *testing.T, cfg ExampleTestConfig, svcName, opName, exampleName string, actual map[string]any) {
+	t.Helper()
+
+	if cfg.SnapshotDir == "" {
+		t.Fatalf("UPDATE_SNAPSHOTS=1 requires ExampleTestConfig.SnapshotDir to be set")
+	}
+
+	redacted := applyRedactors(cfg.Redactors, cloneJSON(actual))
+	checkMatchers(t, cfg.Matchers, redacted)
+	maskMatchedPaths(cfg.Matchers, redacted)
+
+	path := filepath.Join(cfg.SnapshotDir, svcName, opName, exampleName+".json")
+
+	if os.Getenv("UPDATE_SNAPSHOTS") == "1" {
+		if err := writeSnapshot(path, redacted); err != nil {
+			t.Fatalf("failed to write snapshot %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := readSnapshot(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if writeErr := writeSnapshot(path, redacted); writeErr != nil {
+				t.Fatalf("failed to create new snapshot %s: %v", path, writeErr)
+			}
+			return
+		}
+		t.Fatalf("failed to read snapshot %s: %v", path, err)
+	}
+
+	verifySnapshot(t, expected, redacted)
+}
+
+// cloneJSON deep-copies a JSON-shaped value via round-trip encoding, so
+// redaction/masking never mutates the caller's result.Data.
+func cloneJSON(data map[string]any) map[string]any {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var clone map[string]any
+	if err := json.Unmarshal(encoded, &clone); err != nil {
+		return data
+	}
+	return clone
+}
+
+// applyRedactors runs data through each redactor in order.
+func applyRedactors(redactors []func(map[string]any) map[string]any, actual map[string]any) map[string]any {
+	data := actual
+	for _, redact := range redactors {
+		data = redact(data)
+	}
+	return data
+}
+
+// MatchFunc loosely validates a single value at a JSON path instead of
+// requiring an exact match (e.g. "latency_ms" varies between runs).
+type MatchFunc func(actual any) bool
+
+// IsNumberGT returns a MatchFunc that accepts any numeric value greater
+// than n.
+func IsNumberGT(n float64) MatchFunc {
+	return func(actual any) bool {
+		v, ok := toFloat64(actual)
+		return ok && v > n
+	}
+}
+
+// checkMatchers runs every configured matcher against data, reporting a test
+// failure for any path that is missing or fails its matcher.
+func checkMatchers(t *testing.T, matchers map[string]MatchFunc, data map[string]any) {
+	t.Helper()
+
+	paths := make([]string, 0, len(matchers))
+	for path := range matchers {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		val, ok := getByPath(data, path)
+		if !ok {
+			t.Errorf("matcher for %q: field not found", path)
+			continue
+		}
+		if !matchers[path](val) {
+			t.Errorf("matcher for %q: value %v did not match", path, val)
+		}
+	}
+}
+
+// maskMatchedPaths replaces values covered by a matcher with a fixed
+// placeholder, so volatile fields don't break the snapshot diff.
+func maskMatchedPaths(matchers map[string]MatchFunc, data map[string]any) {
+	for path := range matchers {
+		setByPath(data, path, "<matched>")
+	}
+}
+
+// getByPath resolves a dot-separated JSON path against nested maps.
+func getByPath(data map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	var cur any = data
+	for _, part := range parts {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// setByPath overwrites the value at a dot-separated JSON path, if present.
+func setByPath(data map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	cur := data
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			if _, ok := cur[part]; ok {
+				cur[part] = value
+			}
+			return
+		}
+		next, ok := cur[part].(map[string]any)
+		if !ok {
+			return
+		}
+		cur = next
+	}
+}
+
+// writeSnapshot serializes data as canonical (key-sorted, indented) JSON.
+func writeSnapshot(path string, data map[string]any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(encoded, '\n'), 0o644)
+}
+
+// readSnapshot loads and decodes a previously written golden file.
+func readSnapshot(path string) (map[string]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// verifySnapshot compares expected and actual field-by-field using the same
+// numeric-aware comparison as verifyOutput, but in both directions so that
+// fields added to the output are also reported. The two-way check covers
+// top-level fields only; nested objects are compared by deepEqual, which
+// checks just the expected keys.
+func verifySnapshot(t *testing.T, expected, actual map[string]any) {
+	t.Helper()
+
+	for key, expectedVal := range expected {
+		actualVal, ok := actual[key]
+		if !ok {
+			t.Errorf("snapshot mismatch: missing field %q in output", key)
+			continue
+		}
+		if !deepEqual(expectedVal, actualVal) {
+			t.Errorf("snapshot mismatch: field %q: expected %v (%T), got %v (%T)",
+				key, expectedVal, expectedVal, actualVal, actualVal)
+		}
+	}
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			t.Errorf("snapshot mismatch: unexpected field %q in output", key)
+		}
 	}
 }
 
@@ -188,6 +390,24 @@ type ExampleTestConfig struct {
 	// MockClientFactory creates a mock client for each test
 	// If nil, uses the client passed to GenerateExampleTests
 	MockClientFactory func(exampleName string) any
+
+	// SnapshotDir enables golden-file testing: when an example has no
+	// ExpectedOutput, result.Data is compared against (or, on first run or
+	// when UPDATE_SNAPSHOTS=1, written to) SnapshotDir/<svc>/<op>/<example>.json.
+	SnapshotDir string
+
+	// Redactors scrub volatile fields (timestamps, UUIDs, IPs, ...) from
+	// result.Data before it is written to or compared against a snapshot.
+	Redactors []func(map[string]any) map[string]any
+
+	// Matchers loosely validate specific JSON paths (dot-separated, e.g.
+	// "latency_ms") instead of requiring an exact snapshot match.
+	Matchers map[string]MatchFunc
+
+	// IncludeSchemaBoundaries also runs the boundary-value inputs produced by
+	// GenerateExamplesFromSchema for each operation, in addition to its
+	// hand-written Examples.
+	IncludeSchemaBoundaries bool
 }
 
 // GenerateExampleTestsWithConfig creates tests with custom configuration.
@@ -207,7 +427,12 @@ func GenerateExampleTestsWithConfig(
 
 	for svcName, svc := range manifest.Services {
 		for _, op := range svc.Operations {
-			for _, ex := range op.Examples {
+			examples := op.Examples
+			if config.IncludeSchemaBoundaries {
+				examples = append(examples, GenerateExamplesFromSchema(op)...)
+			}
+
+			for _, ex := range examples {
 				if skipSet[ex.Name] {
 					continue
 				}
@@ -219,7 +444,7 @@ func GenerateExampleTestsWithConfig(
 					if config.MockClientFactory != nil {
 						client = config.MockClientFactory(ex.Name)
 					}
-					runExampleTest(t, plugin, svcName, op.Name, ex, client)
+					runExampleTest(t, plugin, svcName, op.Name, ex, client, config)
 				})
 			}
 		}
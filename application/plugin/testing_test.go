@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSnapshotTest_WritesOnFirstRunThenCompares(t *testing.T) {
+	dir := t.TempDir()
+	cfg := ExampleTestConfig{SnapshotDir: dir}
+	data := map[string]any{"status": "ok", "count": float64(2)}
+
+	runSnapshotTest(t, cfg, "svc", "op", "basic", data)
+
+	path := filepath.Join(dir, "svc", "op", "basic.json")
+	require.FileExists(t, path)
+
+	runSnapshotTest(t, cfg, "svc", "op", "basic", data)
+}
+
+func TestRunSnapshotTest_MismatchFails(t *testing.T) {
+	dir := t.TempDir()
+	cfg := ExampleTestConfig{SnapshotDir: dir}
+
+	runSnapshotTest(t, cfg, "svc", "op", "basic", map[string]any{"status": "ok"})
+
+	fakeT := &testing.T{}
+	runSnapshotTest(fakeT, cfg, "svc", "op", "basic", map[string]any{"status": "changed"})
+	assert.True(t, fakeT.Failed())
+}
+
+func TestRunSnapshotTest_RedactorsScrubVolatileFields(t *testing.T) {
+	dir := t.TempDir()
+	redactTimestamp := func(m map[string]any) map[string]any {
+		m["timestamp"] = "<redacted>"
+		return m
+	}
+	cfg := ExampleTestConfig{SnapshotDir: dir, Redactors: []func(map[string]any) map[string]any{redactTimestamp}}
+
+	runSnapshotTest(t, cfg, "svc", "op", "basic", map[string]any{"status": "ok", "timestamp": "2026-07-26T00:00:00Z"})
+	runSnapshotTest(t, cfg, "svc", "op", "basic", map[string]any{"status": "ok", "timestamp": "2026-07-27T00:00:00Z"})
+}
+
+func TestRunSnapshotTest_MatchersAllowVolatileFields(t *testing.T) {
+	dir := t.TempDir()
+	cfg := ExampleTestConfig{
+		SnapshotDir: dir,
+		Matchers:    map[string]MatchFunc{"latency_ms": IsNumberGT(0)},
+	}
+
+	runSnapshotTest(t, cfg, "svc", "op", "basic", map[string]any{"latency_ms": float64(12)})
+
+	fakeT := &testing.T{}
+	runSnapshotTest(fakeT, cfg, "svc", "op", "basic", map[string]any{"latency_ms": float64(0)})
+	assert.True(t, fakeT.Failed())
+}
+
+func TestRunSnapshotTest_UpdateSnapshotsEnvOverwrites(t *testing.T) {
+	dir := t.TempDir()
+	cfg := ExampleTestConfig{SnapshotDir: dir}
+
+	runSnapshotTest(t, cfg, "svc", "op", "basic", map[string]any{"status": "old"})
+
+	t.Setenv("UPDATE_SNAPSHOTS", "1")
+	runSnapshotTest(t, cfg, "svc", "op", "basic", map[string]any{"status": "new"})
+
+	raw, err := os.ReadFile(filepath.Join(dir, "svc", "op", "basic.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "new")
+}
+
+func TestGetByPathAndSetByPath(t *testing.T) {
+	data := map[string]any{
+		"a": map[string]any{
+			"b": float64(1),
+		},
+	}
+
+	v, ok := getByPath(data, "a.b")
+	require.True(t, ok)
+	assert.Equal(t, float64(1), v)
+
+	_, ok = getByPath(data, "a.missing")
+	assert.False(t, ok)
+
+	setByPath(data, "a.b", "<matched>")
+	v, ok = getByPath(data, "a.b")
+	require.True(t, ok)
+	assert.Equal(t, "<matched>", v)
+}
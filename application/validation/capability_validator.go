@@ -0,0 +1,159 @@
+// Package validation checks a plugin manifest's declared capabilities
+// against JSON schemas for each capability kind (network, fs, env, exec,
+// kv, icmp), so a host can reject a manifest that grants malformed or
+// unsupported capability rules before ever loading the plugin.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Registry supplies the JSON schema for a capability kind. Hosts can
+// implement their own, or use capabilityschema.DefaultRegistry for the
+// SDK's bundled, versioned schemas.
+type Registry interface {
+	Register(name string, capability interface{}) error
+	GetSchema(name string) (string, bool)
+	List() []string
+}
+
+// versionedRegistry is satisfied by a Registry that knows which schema
+// version it serves, such as capabilityschema.DefaultRegistry. Checked via
+// type assertion so registries that don't support versioning (like a bare
+// host-supplied map) keep working unchanged.
+type versionedRegistry interface {
+	Version() string
+}
+
+// ValidationResult reports whether a manifest's capabilities passed schema
+// validation, and every failure encountered along the way.
+type ValidationResult struct {
+	Valid  bool
+	Errors []ValidationError
+}
+
+// ValidationError describes a single capability section that failed
+// validation.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// CapabilityValidator validates manifest capabilities using JSON schemas
+// pulled from a Registry.
+type CapabilityValidator struct {
+	registry Registry
+	compiler *jsonschema.Compiler
+}
+
+// NewCapabilityValidator creates a validator backed by registry.
+func NewCapabilityValidator(registry Registry) *CapabilityValidator {
+	return &CapabilityValidator{
+		registry: registry,
+		compiler: jsonschema.NewCompiler(),
+	}
+}
+
+// Validate checks the manifest's declared capabilities against the
+// registry's schemas, and rejects the manifest outright if it pins a
+// CapabilitySchemaVersion newer than what the registry serves.
+func (v *CapabilityValidator) Validate(manifest *entities.Manifest) (*ValidationResult, error) {
+	result := &ValidationResult{Valid: true}
+
+	if manifest.CapabilitySchemaVersion != "" {
+		if vr, ok := v.registry.(versionedRegistry); ok {
+			hostVersion := vr.Version()
+			if newer, err := isNewerVersion(manifest.CapabilitySchemaVersion, hostVersion); err == nil && newer {
+				result.Valid = false
+				result.Errors = append(result.Errors, ValidationError{
+					Field: "capability_schema_version",
+					Message: fmt.Sprintf(
+						"plugin pins capability schema version %q, host only bundles %q",
+						manifest.CapabilitySchemaVersion, hostVersion,
+					),
+				})
+				return result, nil
+			}
+		}
+	}
+
+	validateSection := func(kind string, data interface{}) {
+		if data == nil {
+			return
+		}
+		schemaStr, ok := v.registry.GetSchema(kind)
+		if !ok {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   kind,
+				Message: fmt.Sprintf("no schema registered for capability kind: %s", kind),
+			})
+			return
+		}
+		if err := v.compiler.AddResource(kind, strings.NewReader(schemaStr)); err != nil {
+			if !strings.Contains(err.Error(), "already exists") {
+				result.Valid = false
+				result.Errors = append(result.Errors, ValidationError{
+					Field:   kind,
+					Message: fmt.Sprintf("failed to add schema resource for %s: %v", kind, err),
+				})
+				return
+			}
+		}
+		sch, err := v.compiler.Compile(kind)
+		if err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   kind,
+				Message: fmt.Sprintf("invalid schema for %s: %v", kind, err),
+			})
+			return
+		}
+		b, _ := json.Marshal(data)
+		var obj interface{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   kind,
+				Message: fmt.Sprintf("failed to prepare validation object: %v", err),
+			})
+			return
+		}
+		if err := sch.Validate(obj); err != nil {
+			result.Valid = false
+			var ve *jsonschema.ValidationError
+			if errors.As(err, &ve) {
+				result.Errors = append(result.Errors, ValidationError{Field: kind, Message: ve.Error()})
+			} else {
+				result.Errors = append(result.Errors, ValidationError{Field: kind, Message: err.Error()})
+			}
+		}
+	}
+
+	grants := manifest.Capabilities
+	if grants.Network != nil && len(grants.Network.Rules) > 0 {
+		validateSection("network", grants.Network)
+	}
+	if grants.FS != nil && len(grants.FS.Rules) > 0 {
+		validateSection("fs", grants.FS)
+	}
+	if grants.Env != nil && len(grants.Env.Variables) > 0 {
+		validateSection("env", grants.Env)
+	}
+	if grants.Exec != nil && len(grants.Exec.Commands) > 0 {
+		validateSection("exec", grants.Exec)
+	}
+	if grants.KV != nil && len(grants.KV.Rules) > 0 {
+		validateSection("kv", grants.KV)
+	}
+	if grants.ICMP != nil && len(grants.ICMP.Rules) > 0 {
+		validateSection("icmp", grants.ICMP)
+	}
+	return result, nil
+}
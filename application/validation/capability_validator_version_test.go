@@ -0,0 +1,49 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/application/validation"
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versionedMockRegistry struct {
+	mockRegistry
+	version string
+}
+
+func (m *versionedMockRegistry) Version() string { return m.version }
+
+func TestCapabilityValidator_RejectsNewerPinnedSchemaVersion(t *testing.T) {
+	registry := &versionedMockRegistry{version: "v1"}
+	validator := validation.NewCapabilityValidator(registry)
+
+	manifest := &entities.Manifest{
+		Version:                 "1.0.0",
+		CapabilitySchemaVersion: "v2",
+		Capabilities:            entities.GrantSet{},
+	}
+
+	res, err := validator.Validate(manifest)
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, "capability_schema_version", res.Errors[0].Field)
+}
+
+func TestCapabilityValidator_AcceptsPinnedSchemaVersionAtOrBelowHost(t *testing.T) {
+	registry := &versionedMockRegistry{version: "v1"}
+	validator := validation.NewCapabilityValidator(registry)
+
+	manifest := &entities.Manifest{
+		Version:                 "1.0.0",
+		CapabilitySchemaVersion: "v1",
+		Capabilities:            entities.GrantSet{},
+	}
+
+	res, err := validator.Validate(manifest)
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// isNewerVersion reports whether pinned is a newer schema version than
+// bundled. Versions are dotted numeric strings with an optional leading
+// "v" (e.g. "v1", "v1.2", "1.2.3"); components are compared numerically
+// left to right, and a missing trailing component is treated as 0.
+func isNewerVersion(pinned, bundled string) (bool, error) {
+	p, err := parseVersion(pinned)
+	if err != nil {
+		return false, err
+	}
+	b, err := parseVersion(bundled)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < len(p) || i < len(b); i++ {
+		var pv, bv int
+		if i < len(p) {
+			pv = p[i]
+		}
+		if i < len(b) {
+			bv = b[i]
+		}
+		if pv != bv {
+			return pv > bv, nil
+		}
+	}
+	return false, nil
+}
+
+func parseVersion(version string) ([]int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if trimmed == "" {
+		return nil, fmt.Errorf("validation: empty version")
+	}
+	parts := strings.Split(trimmed, ".")
+	out := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("validation: invalid version %q: %w", version, err)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
@@ -0,0 +1,32 @@
+package validation
+
+import "testing"
+
+func TestIsNewerVersion(t *testing.T) {
+	cases := []struct {
+		pinned, bundled string
+		want            bool
+	}{
+		{"v1", "v1", false},
+		{"v2", "v1", true},
+		{"v1", "v2", false},
+		{"v1.2", "v1.10", false},
+		{"v1.10", "v1.2", true},
+		{"1", "v1", false},
+	}
+	for _, c := range cases {
+		got, err := isNewerVersion(c.pinned, c.bundled)
+		if err != nil {
+			t.Fatalf("isNewerVersion(%q, %q): %v", c.pinned, c.bundled, err)
+		}
+		if got != c.want {
+			t.Errorf("isNewerVersion(%q, %q) = %v, want %v", c.pinned, c.bundled, got, c.want)
+		}
+	}
+}
+
+func TestIsNewerVersion_InvalidVersion(t *testing.T) {
+	if _, err := isNewerVersion("not-a-version", "v1"); err == nil {
+		t.Error("expected error for unparseable pinned version")
+	}
+}
@@ -25,3 +25,18 @@ type KeyValueCapability = hostfunc.KeyValueCapability
 
 // KeyValueRule defines a single key-value access rule.
 type KeyValueRule = hostfunc.KeyValueRule
+
+// ICMPCapability defines permitted ICMP probing, restricting target CIDRs
+// and the maximum probe rate a plugin may issue.
+type ICMPCapability = hostfunc.ICMPCapability
+
+// ICMPRule defines a single ICMP probing rule.
+type ICMPRule = hostfunc.ICMPRule
+
+// TracingCapability defines whether a plugin may export spans to the host's
+// trace pipeline, and at what sampling rate.
+type TracingCapability = hostfunc.TracingCapability
+
+// LoggingCapability defines the minimum level and the structured attribute
+// keys a plugin may emit to the host's log pipeline.
+type LoggingCapability = hostfunc.LoggingCapability
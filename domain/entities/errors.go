@@ -0,0 +1,153 @@
+package entities
+
+import "fmt"
+
+// The SDK gives handler authors a small set of typed errors so that
+// application/plugin can classify a failure (timeout, capability denial,
+// bad config, network fault, DNS/HTTP/TCP/exec/validation/key-value fault)
+// instead of flattening every returned error into a generic "execution"
+// failure. Return one of these from a typed handler and the host will see
+// the matching Result.Error.Type and .Code.
+
+// TimeoutError indicates an operation exceeded its allotted time budget.
+type TimeoutError struct {
+	Operation string
+	Err       error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out: %v", e.Operation, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// Timeout reports true, satisfying the net.Error-style Timeout() convention
+// that classifyError checks for.
+func (e *TimeoutError) Timeout() bool { return true }
+
+// CapabilityError indicates a handler attempted to use a capability the
+// plugin was not granted.
+type CapabilityError struct {
+	Required string
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("capability %q not granted", e.Required)
+}
+
+// ConfigError indicates the operation's input or the plugin's config failed
+// validation.
+type ConfigError struct {
+	Field string
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("config: %v", e.Err)
+	}
+	return fmt.Sprintf("config: %s: %v", e.Field, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// NetworkError indicates a handler-initiated network operation failed.
+type NetworkError struct {
+	Operation string
+	Err       error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Operation, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// DNSError indicates a handler-initiated DNS lookup failed.
+type DNSError struct {
+	Hostname   string
+	RecordType string
+	Err        error
+}
+
+func (e *DNSError) Error() string {
+	return fmt.Sprintf("dns lookup for %s (%s) failed: %v", e.Hostname, e.RecordType, e.Err)
+}
+
+func (e *DNSError) Unwrap() error { return e.Err }
+
+// HTTPError indicates a handler-initiated HTTP request failed.
+type HTTPError struct {
+	Method     string
+	URL        string
+	StatusCode int // 0 if the request failed before a response was received.
+	Err        error
+}
+
+func (e *HTTPError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("http %s %s failed with status %d: %v", e.Method, e.URL, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("http %s %s failed: %v", e.Method, e.URL, e.Err)
+}
+
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// TCPError indicates a handler-initiated TCP connection failed.
+type TCPError struct {
+	Address string
+	Err     error
+}
+
+func (e *TCPError) Error() string {
+	return fmt.Sprintf("tcp connect to %s failed: %v", e.Address, e.Err)
+}
+
+func (e *TCPError) Unwrap() error { return e.Err }
+
+// ExecError indicates a handler-initiated command execution failed, either
+// because the command couldn't be started (Err set) or because it ran and
+// exited non-zero (ExitCode set, Err nil).
+type ExecError struct {
+	Command  string
+	ExitCode int
+	Err      error
+}
+
+func (e *ExecError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed to execute %q: %v", e.Command, e.Err)
+	}
+	return fmt.Sprintf("command %q exited with code %d", e.Command, e.ExitCode)
+}
+
+func (e *ExecError) Unwrap() error { return e.Err }
+
+// ValidationError indicates a handler's input or output failed schema
+// validation, as distinct from ConfigError's plugin-configuration failures.
+type ValidationError struct {
+	Field string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("validation: %v", e.Err)
+	}
+	return fmt.Sprintf("validation: %s: %v", e.Field, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// KeyValueError indicates a handler attempted a key-value store operation
+// the plugin was not granted.
+type KeyValueError struct {
+	Key string
+	Err error
+}
+
+func (e *KeyValueError) Error() string {
+	return fmt.Sprintf("key-value access to %q denied: %v", e.Key, e.Err)
+}
+
+func (e *KeyValueError) Unwrap() error { return e.Err }
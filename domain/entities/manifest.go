@@ -22,6 +22,13 @@ type Manifest struct {
 
 	// Capabilities (http, dns, file, exec, etc.)
 	Capabilities GrantSet `json:"capabilities" yaml:"capabilities"`
+
+	// CapabilitySchemaVersion pins the capabilityschema version this
+	// plugin was built against, so a host validating Capabilities can
+	// reject the manifest outright if it targets a schema contract newer
+	// than what the host bundles, rather than silently validating against
+	// the wrong rules.
+	CapabilitySchemaVersion string `json:"capability_schema_version,omitempty" yaml:"capability_schema_version,omitempty"`
 }
 
 // ServiceManifest describes a service and its operations.
@@ -39,6 +46,9 @@ type OperationManifest struct {
 	// Input fields this operation requires (subset of plugin config)
 	InputFields []string `json:"input_fields,omitempty"`
 
+	// JSON Schema for the operation's input type
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+
 	// JSON Schema for Result.Data structure
 	OutputSchema json.RawMessage `json:"output_schema,omitempty"`
 
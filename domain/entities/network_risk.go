@@ -0,0 +1,277 @@
+package entities
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// networkRiskRule scores NetworkCapability rules by parsing what each rule
+// actually admits - an exact host, a wildcard, a bare IP, or a CIDR, plus
+// individual ports or port ranges - rather than only special-casing "*"
+// and flattening everything else to a single Medium level.
+type networkRiskRule struct{}
+
+func (networkRiskRule) Evaluate(grants *GrantSet) []RiskFactor {
+	if grants.Network == nil {
+		return nil
+	}
+	var factors []RiskFactor
+	for _, rule := range grants.Network.Rules {
+		matcher := ParseNetworkRule(rule)
+		ruleStr := fmt.Sprintf("Network: %v:%v", rule.Hosts, rule.Ports)
+
+		level, ruleID := RiskNone, ""
+		for _, h := range matcher.Hosts {
+			if hLevel, hRuleID := hostRiskLevel(h); ruleID == "" || hLevel > level {
+				level, ruleID = hLevel, hRuleID
+			}
+		}
+		if ruleID == "" {
+			level, ruleID = RiskMedium, "NET001-ScopedHost"
+		}
+
+		portBump := matcher.HasSensitivePort()
+		if portBump {
+			level = bumpLevel(level)
+		}
+
+		factors = append(factors, RiskFactor{
+			Level: level, Description: networkFactorDescription(ruleID, portBump),
+			Rule: ruleStr, RuleID: ruleID,
+		})
+	}
+	return factors
+}
+
+func networkFactorDescription(ruleID string, portBump bool) string {
+	switch ruleID {
+	case "NET001-WildcardHost":
+		return "Unrestricted network access"
+	case "NET001-OpenCIDR":
+		return "Unrestricted network access (open CIDR)"
+	case "NET001-CloudMetadata":
+		return "Cloud metadata endpoint access"
+	case "NET001-WideCIDR":
+		return "Wide network range access"
+	case "NET001-PrivateRange":
+		return "Internal network access"
+	case "NET001-WildcardSubdomain":
+		return "Wildcard subdomain network access"
+	default:
+		if portBump {
+			return "Outbound network access to a sensitive port"
+		}
+		return "Outbound network access"
+	}
+}
+
+func bumpLevel(level RiskLevel) RiskLevel {
+	if level < RiskCritical {
+		return level + 1
+	}
+	return level
+}
+
+// SensitivePorts are well-known ports for remote shell, RDP, and database
+// access, where the Risk of a bounded Network rule is bumped one level
+// because reaching the port alone is often enough to act on the result
+// (e.g. an open SSH or database port).
+var SensitivePorts = map[int]string{
+	22:    "ssh",
+	3389:  "rdp",
+	3306:  "mysql",
+	5432:  "postgres",
+	6379:  "redis",
+	27017: "mongodb",
+}
+
+// CloudMetadataHosts are cloud-provider instance metadata endpoints. A
+// Network rule admitting any of these always scores Critical: reaching
+// them from a plugin sandbox is an almost direct path to the host's
+// cloud credentials, regardless of what port or path is requested.
+var CloudMetadataHosts = []string{
+	"169.254.169.254",          // AWS, Azure, GCP, DigitalOcean, OCI
+	"metadata.google.internal", // GCP
+	"metadata.goog",
+	"fd00:ec2::254",   // AWS IMDSv2 over IPv6
+	"100.100.100.200", // Alibaba Cloud
+}
+
+func isCloudMetadataHost(host string) bool {
+	for _, meta := range CloudMetadataHosts {
+		if strings.EqualFold(host, meta) {
+			return true
+		}
+	}
+	return false
+}
+
+// cidrContainsCloudMetadataHost reports whether cidr admits any
+// CloudMetadataHosts entry that parses as an IP. This catches a metadata
+// host expressed as a CIDR (e.g. "169.254.169.254/32"), which isCloudMetadataHost
+// alone would miss since it only string-compares the unparsed Raw host.
+func cidrContainsCloudMetadataHost(cidr *net.IPNet) bool {
+	for _, meta := range CloudMetadataHosts {
+		if ip := net.ParseIP(meta); ip != nil && cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// HostMatchKind identifies how a NetworkRule's host entry was parsed.
+type HostMatchKind string
+
+const (
+	HostKindWildcard  HostMatchKind = "wildcard"
+	HostKindSubdomain HostMatchKind = "wildcard_subdomain"
+	HostKindExact     HostMatchKind = "exact"
+	HostKindIP        HostMatchKind = "ip"
+	HostKindCIDR      HostMatchKind = "cidr"
+)
+
+// HostMatch is one parsed entry from a NetworkRule's Hosts list.
+type HostMatch struct {
+	Raw  string
+	Kind HostMatchKind
+	IP   net.IP
+	CIDR *net.IPNet
+}
+
+// ParseHostMatch classifies a single Hosts entry as a wildcard ("*" or
+// "0.0.0.0"), a CIDR, a bare IP, a "*.example.com" wildcard subdomain, or
+// an exact hostname, in that precedence order.
+func ParseHostMatch(host string) HostMatch {
+	if host == "*" || host == "0.0.0.0" || host == "::" {
+		return HostMatch{Raw: host, Kind: HostKindWildcard}
+	}
+	if _, cidr, err := net.ParseCIDR(host); err == nil {
+		return HostMatch{Raw: host, Kind: HostKindCIDR, CIDR: cidr}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return HostMatch{Raw: host, Kind: HostKindIP, IP: ip}
+	}
+	if strings.HasPrefix(host, "*.") {
+		return HostMatch{Raw: host, Kind: HostKindSubdomain}
+	}
+	return HostMatch{Raw: host, Kind: HostKindExact}
+}
+
+// hostRiskLevel scores a single parsed host entry. CloudMetadataHosts take
+// precedence over every other classification.
+func hostRiskLevel(h HostMatch) (RiskLevel, string) {
+	if isCloudMetadataHost(h.Raw) {
+		return RiskCritical, "NET001-CloudMetadata"
+	}
+	switch h.Kind {
+	case HostKindWildcard:
+		return RiskCritical, "NET001-WildcardHost"
+	case HostKindCIDR:
+		if cidrContainsCloudMetadataHost(h.CIDR) {
+			return RiskCritical, "NET001-CloudMetadata"
+		}
+		ones, _ := h.CIDR.Mask.Size()
+		switch {
+		case ones == 0:
+			return RiskCritical, "NET001-OpenCIDR"
+		case isPrivateIP(h.CIDR.IP):
+			return RiskLow, "NET001-PrivateRange"
+		case ones < 8:
+			return RiskHigh, "NET001-WideCIDR"
+		default:
+			return RiskMedium, "NET001-ScopedCIDR"
+		}
+	case HostKindIP:
+		if isPrivateIP(h.IP) {
+			return RiskLow, "NET001-PrivateRange"
+		}
+		return RiskMedium, "NET001-ScopedHost"
+	case HostKindSubdomain:
+		return RiskMedium, "NET001-WildcardSubdomain"
+	default: // HostKindExact
+		return RiskMedium, "NET001-ScopedHost"
+	}
+}
+
+// isPrivateIP reports whether ip is RFC1918/ULA private, loopback, or
+// link-local - network ranges that don't leave the host or local segment.
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
+
+// PortRange is a single port ("443") or an inclusive range ("80-89")
+// parsed from a NetworkRule's Ports list.
+type PortRange struct {
+	Raw       string
+	Low, High int
+}
+
+// ParsePortRange parses a single port or an inclusive "low-high" range.
+func ParsePortRange(s string) (PortRange, error) {
+	s = strings.TrimSpace(s)
+	if lo, hi, ok := strings.Cut(s, "-"); ok {
+		low, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return PortRange{}, fmt.Errorf("network: invalid port range %q: %w", s, err)
+		}
+		high, err := strconv.Atoi(strings.TrimSpace(hi))
+		if err != nil {
+			return PortRange{}, fmt.Errorf("network: invalid port range %q: %w", s, err)
+		}
+		return PortRange{Raw: s, Low: low, High: high}, nil
+	}
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return PortRange{}, fmt.Errorf("network: invalid port %q: %w", s, err)
+	}
+	return PortRange{Raw: s, Low: port, High: port}, nil
+}
+
+// Contains reports whether port falls within the (inclusive) range.
+func (p PortRange) Contains(port int) bool {
+	return port >= p.Low && port <= p.High
+}
+
+// NetworkRuleMatcher is a NetworkRule's Hosts and Ports, parsed into a
+// structured form. It backs the built-in network risk rule, but hosts can
+// reuse it directly (e.g. via ParseNetworkRule) for runtime enforcement
+// instead of re-parsing the raw strings themselves.
+type NetworkRuleMatcher struct {
+	Hosts []HostMatch
+	Ports []PortRange
+}
+
+// ParseNetworkRule parses rule's Hosts and Ports into a NetworkRuleMatcher.
+// Unparseable port entries are skipped rather than erroring, since risk
+// scoring should degrade gracefully on a malformed rule instead of failing
+// closed for the whole GrantSet.
+func ParseNetworkRule(rule NetworkRule) NetworkRuleMatcher {
+	m := NetworkRuleMatcher{
+		Hosts: make([]HostMatch, 0, len(rule.Hosts)),
+		Ports: make([]PortRange, 0, len(rule.Ports)),
+	}
+	for _, h := range rule.Hosts {
+		m.Hosts = append(m.Hosts, ParseHostMatch(h))
+	}
+	for _, p := range rule.Ports {
+		if pr, err := ParsePortRange(p); err == nil {
+			m.Ports = append(m.Ports, pr)
+		}
+	}
+	return m
+}
+
+// HasSensitivePort reports whether any parsed port range admits one of
+// SensitivePorts.
+func (m NetworkRuleMatcher) HasSensitivePort() bool {
+	for port := range SensitivePorts {
+		for _, pr := range m.Ports {
+			if pr.Contains(port) {
+				return true
+			}
+		}
+	}
+	return false
+}
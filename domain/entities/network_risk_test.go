@@ -0,0 +1,67 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRiskAssessor_NetworkCIDRAndPortAwareness(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+
+	cases := []struct {
+		name  string
+		hosts []string
+		ports []string
+		want  entities.RiskLevel
+	}{
+		{"open IPv4 CIDR is Critical", []string{"0.0.0.0/0"}, []string{"443"}, entities.RiskCritical},
+		{"open IPv6 CIDR is Critical", []string{"::/0"}, []string{"443"}, entities.RiskCritical},
+		{"wide public CIDR is High", []string{"8.8.0.0/7"}, []string{"443"}, entities.RiskHigh},
+		{"private RFC1918 range is Low", []string{"10.0.0.0/8"}, []string{"443"}, entities.RiskLow},
+		{"loopback IP is Low", []string{"127.0.0.1"}, []string{"443"}, entities.RiskLow},
+		{"specific public host bounded port is Medium", []string{"example.com"}, []string{"443"}, entities.RiskMedium},
+		{"specific public host with a sensitive port bumps to High", []string{"example.com"}, []string{"5432"}, entities.RiskHigh},
+		{"cloud metadata host is always Critical", []string{"169.254.169.254"}, []string{"80"}, entities.RiskCritical},
+		{"cloud metadata host as a /32 CIDR is still Critical", []string{"169.254.169.254/32"}, []string{"80"}, entities.RiskCritical},
+		{"sensitive port inside a range still bumps", []string{"example.com"}, []string{"3300-3400"}, entities.RiskHigh},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &entities.GrantSet{
+				Network: &entities.NetworkCapability{
+					Rules: []entities.NetworkRule{{Hosts: tc.hosts, Ports: tc.ports}},
+				},
+			}
+			report := assessor.Analyze(g)
+			assert.Equal(t, tc.want, report.Level)
+		})
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	pr, err := entities.ParsePortRange("80-89")
+	assert.NoError(t, err)
+	assert.Equal(t, 80, pr.Low)
+	assert.Equal(t, 89, pr.High)
+	assert.True(t, pr.Contains(85))
+	assert.False(t, pr.Contains(90))
+
+	single, err := entities.ParsePortRange("443")
+	assert.NoError(t, err)
+	assert.Equal(t, 443, single.Low)
+	assert.Equal(t, 443, single.High)
+
+	_, err = entities.ParsePortRange("not-a-port")
+	assert.Error(t, err)
+}
+
+func TestParseHostMatch(t *testing.T) {
+	assert.Equal(t, entities.HostKindWildcard, entities.ParseHostMatch("*").Kind)
+	assert.Equal(t, entities.HostKindCIDR, entities.ParseHostMatch("10.0.0.0/8").Kind)
+	assert.Equal(t, entities.HostKindIP, entities.ParseHostMatch("127.0.0.1").Kind)
+	assert.Equal(t, entities.HostKindSubdomain, entities.ParseHostMatch("*.example.com").Kind)
+	assert.Equal(t, entities.HostKindExact, entities.ParseHostMatch("example.com").Kind)
+}
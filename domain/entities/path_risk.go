@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// PathClass classifies the sensitivity of a filesystem path for risk
+// scoring purposes, so the FS rule can weigh "read /etc/shadow" very
+// differently from "read /tmp/scratch.txt" instead of scoring every read
+// (or every write) the same regardless of target.
+type PathClass string
+
+const (
+	// PathClassSystem covers OS configuration and state directories.
+	PathClassSystem PathClass = "system"
+	// PathClassUserSecrets covers per-user credential directories.
+	PathClassUserSecrets PathClass = "user_secrets"
+	// PathClassBinaries covers executable search-path directories.
+	PathClassBinaries PathClass = "binaries"
+	// PathClassWorkspace covers the plugin's own sandbox root.
+	PathClassWorkspace PathClass = "workspace"
+	// PathClassEphemeral covers scratch directories cleared between runs.
+	PathClassEphemeral PathClass = "ephemeral"
+	// PathClassUnknown is anything matching none of the above.
+	PathClassUnknown PathClass = "unknown"
+)
+
+// PathClassifier assigns a PathClass to filesystem paths by prefix. The
+// built-in prefixes cover common Unix conventions; a host registers
+// additional sensitive prefixes at startup via RegisterSensitivePrefix
+// (which mutates the package-level default classifier) or by building its
+// own with NewPathClassifier for use outside the default rule pipeline.
+type PathClassifier struct {
+	mu            sync.RWMutex
+	prefixes      map[PathClass][]string
+	workspaceRoot string
+}
+
+// NewPathClassifier returns a PathClassifier seeded with the default
+// System/UserSecrets/Binaries/Ephemeral prefixes. Binaries also picks up
+// every directory in the current process's PATH, and UserSecrets picks up
+// the real home directory in addition to the literal "~/..." prefixes, so
+// classification works against both tilde-form and already-expanded paths.
+func NewPathClassifier() *PathClassifier {
+	return &PathClassifier{
+		prefixes: map[PathClass][]string{
+			PathClassSystem:      {"/etc", "/boot", "/sys", "/proc", "/var/lib"},
+			PathClassUserSecrets: homeSecretPrefixes(),
+			PathClassBinaries:    binaryPrefixes(),
+			PathClassEphemeral:   {"/tmp", "/var/tmp"},
+		},
+	}
+}
+
+func homeSecretPrefixes() []string {
+	prefixes := []string{"~/.ssh", "~/.aws", "~/.config", "~/.kube"}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return prefixes
+	}
+	for _, suffix := range []string{"/.ssh", "/.aws", "/.config", "/.kube"} {
+		prefixes = append(prefixes, home+suffix)
+	}
+	return prefixes
+}
+
+func binaryPrefixes() []string {
+	prefixes := []string{"/usr/bin", "/bin"}
+	if path := os.Getenv("PATH"); path != "" {
+		prefixes = append(prefixes, strings.Split(path, string(os.PathListSeparator))...)
+	}
+	return prefixes
+}
+
+// Register adds prefix to class, so a path starting with it classifies as
+// class from then on. Registering a prefix for a class that's already
+// built-in (e.g. a second System directory) just extends that class's
+// list; it doesn't replace it.
+func (c *PathClassifier) Register(class PathClass, prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.prefixes == nil {
+		c.prefixes = make(map[PathClass][]string)
+	}
+	c.prefixes[class] = append(c.prefixes[class], prefix)
+}
+
+// SetWorkspaceRoot sets the plugin sandbox root that classifies as
+// PathClassWorkspace. Empty (the default) disables workspace
+// classification, since the SDK has no fixed sandbox root of its own.
+func (c *PathClassifier) SetWorkspaceRoot(root string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workspaceRoot = root
+}
+
+// Classify returns p's PathClass. Workspace is checked first so a sandbox
+// root nested under, say, /tmp still classifies as Workspace rather than
+// Ephemeral.
+func (c *PathClassifier) Classify(p string) PathClass {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p = filepath.Clean(p)
+	if c.workspaceRoot != "" && hasPathPrefix(p, filepath.Clean(c.workspaceRoot)) {
+		return PathClassWorkspace
+	}
+	for _, class := range [...]PathClass{PathClassSystem, PathClassUserSecrets, PathClassBinaries, PathClassEphemeral} {
+		for _, prefix := range c.prefixes[class] {
+			if prefix != "" && hasPathPrefix(p, filepath.Clean(prefix)) {
+				return class
+			}
+		}
+	}
+	return PathClassUnknown
+}
+
+// hasPathPrefix reports whether p is prefix itself or a descendant of it,
+// requiring prefix to end on a directory boundary. A raw strings.HasPrefix
+// would also match unrelated siblings that merely share characters (e.g.
+// "/sandbox/plugin-1" "matching" "/sandbox/plugin-10/evil", or "/tmp"
+// matching "/tmp-secrets/id_rsa"). p and prefix must already be
+// filepath.Clean-ed.
+func hasPathPrefix(p, prefix string) bool {
+	return p == prefix || strings.HasPrefix(p, prefix+string(os.PathSeparator))
+}
+
+// defaultPathClassifier backs the built-in filesystem rule used by
+// NewSimpleRiskAnalyzer and a no-args NewRiskAnalyzer.
+// RegisterSensitivePrefix/SetWorkspaceRoot mutate it directly, mirroring
+// RegisterRule/DisableRule for the rule registry.
+var defaultPathClassifier = NewPathClassifier()
+
+// RegisterSensitivePrefix adds prefix to class in the default classifier.
+func RegisterSensitivePrefix(class PathClass, prefix string) {
+	defaultPathClassifier.Register(class, prefix)
+}
+
+// SetWorkspaceRoot sets the plugin sandbox root in the default classifier.
+func SetWorkspaceRoot(root string) {
+	defaultPathClassifier.SetWorkspaceRoot(root)
+}
@@ -0,0 +1,100 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRiskAssessor_PathSensitivityClasses(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+
+	cases := []struct {
+		name string
+		rule entities.FileSystemRule
+		want entities.RiskLevel
+	}{
+		{"write to System is Critical", entities.FileSystemRule{Write: []string{"/etc/passwd"}}, entities.RiskCritical},
+		{"write to UserSecrets is Critical", entities.FileSystemRule{Write: []string{"~/.ssh/authorized_keys"}}, entities.RiskCritical},
+		{"read of UserSecrets is High", entities.FileSystemRule{Read: []string{"~/.aws/credentials"}}, entities.RiskHigh},
+		{"read of Ephemeral is None", entities.FileSystemRule{Read: []string{"/tmp/scratch.txt"}}, entities.RiskNone},
+		{"recursive glob is Critical regardless of op", entities.FileSystemRule{Read: []string{"~/.ssh/**"}}, entities.RiskCritical},
+		{"traversal pattern is High", entities.FileSystemRule{Read: []string{"/data/../etc/passwd"}}, entities.RiskHigh},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &entities.GrantSet{FS: &entities.FileSystemCapability{Rules: []entities.FileSystemRule{tc.rule}}}
+			report := assessor.Analyze(g)
+			assert.Equal(t, tc.want, report.Level)
+		})
+	}
+}
+
+func TestRiskAssessor_WorkspaceWriteIsLow(t *testing.T) {
+	entities.SetWorkspaceRoot("/sandbox/plugin-1")
+	defer entities.SetWorkspaceRoot("")
+
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		FS: &entities.FileSystemCapability{
+			Rules: []entities.FileSystemRule{{Write: []string{"/sandbox/plugin-1/output.txt"}}},
+		},
+	}
+	report := assessor.Analyze(g)
+	assert.Equal(t, entities.RiskLow, report.Level)
+}
+
+func TestRiskAssessor_SiblingWorkspaceIsNotLow(t *testing.T) {
+	entities.SetWorkspaceRoot("/sandbox/plugin-1")
+	defer entities.SetWorkspaceRoot("")
+
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		FS: &entities.FileSystemCapability{
+			Rules: []entities.FileSystemRule{{Write: []string{"/sandbox/plugin-10/evil"}}},
+		},
+	}
+	report := assessor.Analyze(g)
+	assert.NotEqual(t, entities.RiskLow, report.Level)
+}
+
+func TestPathClassifier_Classify(t *testing.T) {
+	c := entities.NewPathClassifier()
+	assert.Equal(t, entities.PathClassSystem, c.Classify("/etc/shadow"))
+	assert.Equal(t, entities.PathClassEphemeral, c.Classify("/tmp/foo"))
+	assert.Equal(t, entities.PathClassBinaries, c.Classify("/usr/bin/ls"))
+	assert.Equal(t, entities.PathClassUnknown, c.Classify("/data/report.csv"))
+}
+
+func TestPathClassifier_PrefixRequiresDirectoryBoundary(t *testing.T) {
+	c := entities.NewPathClassifier()
+	c.SetWorkspaceRoot("/sandbox/plugin-1")
+
+	// A sibling sandbox that merely shares a string prefix must not
+	// classify as this plugin's own Workspace.
+	assert.Equal(t, entities.PathClassUnknown, c.Classify("/sandbox/plugin-10/evil"))
+	assert.Equal(t, entities.PathClassWorkspace, c.Classify("/sandbox/plugin-1/output.txt"))
+	assert.Equal(t, entities.PathClassWorkspace, c.Classify("/sandbox/plugin-1"))
+
+	// Same boundary requirement for the built-in sensitivity prefixes.
+	assert.Equal(t, entities.PathClassUnknown, c.Classify("/tmp-secrets/id_rsa"))
+	assert.Equal(t, entities.PathClassEphemeral, c.Classify("/tmp/scratch.txt"))
+}
+
+func TestPathClassifier_RegisterSensitivePrefix(t *testing.T) {
+	c := entities.NewPathClassifier()
+	c.Register(entities.PathClassSystem, "/opt/vault")
+	assert.Equal(t, entities.PathClassSystem, c.Classify("/opt/vault/config.json"))
+}
+
+func TestRegisterSensitivePrefix_ExtendsDefaultClassifier(t *testing.T) {
+	entities.RegisterSensitivePrefix(entities.PathClassUserSecrets, "/opt/secrets")
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		FS: &entities.FileSystemCapability{Rules: []entities.FileSystemRule{{Write: []string{"/opt/secrets/token"}}}},
+	}
+	report := assessor.Analyze(g)
+	assert.Equal(t, entities.RiskCritical, report.Level)
+}
@@ -0,0 +1,562 @@
+package entities
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// RiskLevel represents the security risk level of a capability.
+type RiskLevel int
+
+const (
+	RiskNone RiskLevel = iota
+	RiskLow
+	RiskMedium
+	RiskHigh
+	RiskCritical
+)
+
+func (r RiskLevel) String() string {
+	switch r {
+	case RiskNone:
+		return "NONE"
+	case RiskLow:
+		return "LOW"
+	case RiskMedium:
+		return "MEDIUM"
+	case RiskHigh:
+		return "HIGH"
+	case RiskCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// RiskAnalyzer assesses the risk of a requested GrantSet.
+type RiskAnalyzer interface {
+	Analyze(grants *GrantSet) RiskReport
+}
+
+// RiskReport contains the risk assessment results.
+type RiskReport struct {
+	Level       RiskLevel
+	RiskFactors []RiskFactor
+	// Score sums a weight per factor (see riskWeight) across RiskFactors,
+	// so two High factors and one Medium factor (7+7+3 = 17) outrank a
+	// single Critical one (15): Level alone can't distinguish "one bad
+	// thing" from "several moderately bad things at once".
+	Score int
+	// Combinations are the CombinationRules that fired because their
+	// Requires RuleIDs all appeared among RiskFactors - see
+	// evaluateCombinations. A fired combination's Level can raise Level
+	// above what any single RiskFactor would have produced on its own.
+	Combinations []RiskCombination
+}
+
+// RiskFactor describes a specific risky capability.
+type RiskFactor struct {
+	Level       RiskLevel
+	Description string
+	// Rule is a human-readable representation of the specific rule causing this factor.
+	Rule string
+	// RuleID is the stable identifier of the check that produced this
+	// factor (e.g. "NET001-WildcardHost"), so policy overrides and
+	// combinatorial escalation rules can key off of it instead of the
+	// human-readable Description, which is free to change.
+	RuleID string
+	// Severity overrides Level when set, letting a policy layer (see
+	// NewPolicyRiskAnalyzer) down/upgrade this specific factor without
+	// the rule that produced it knowing about the override. Nil means
+	// no override is in effect.
+	Severity *RiskLevel
+	// PolicySource explains why Severity is set: "default" means a
+	// PolicyRiskAnalyzer considered this factor but found no override,
+	// "policy:hosts.deny"/"policy:hosts.allow"/"policy:paths"/
+	// "policy:exec_allow"/"policy:severities" name the RiskPolicy section
+	// that forced it. Empty when no RiskPolicy was involved at all.
+	PolicySource string
+}
+
+// EffectiveLevel returns Severity if an override is set, otherwise Level.
+// RiskReport.Level and Score are computed from this, not from Level
+// directly, so a policy override always wins.
+func (f RiskFactor) EffectiveLevel() RiskLevel {
+	if f.Severity != nil {
+		return *f.Severity
+	}
+	return f.Level
+}
+
+// RiskRule evaluates one slice of a GrantSet - network, filesystem, exec,
+// and so on - and reports the RiskFactors it finds. A RiskAnalyzer built by
+// NewRiskAnalyzer runs a GrantSet through an ordered list of these, so a
+// host can compose its own policy out of the built-in rules, or supply its
+// own, without forking the analyzer.
+type RiskRule interface {
+	Evaluate(grants *GrantSet) []RiskFactor
+}
+
+// PipelineRiskAnalyzer runs a GrantSet through an ordered list of RiskRules
+// and combines their factors into a single RiskReport, taking the highest
+// factor's EffectiveLevel as the report's overall Level.
+type PipelineRiskAnalyzer struct {
+	rules []RiskRule
+}
+
+// NewPipelineRiskAnalyzer builds a RiskAnalyzer directly from already
+// constructed RiskRules, bypassing the RuleDefinition registry entirely.
+// Most callers want NewRiskAnalyzer or NewSimpleRiskAnalyzer instead; this
+// is for cases those can't express, such as an intentionally empty
+// pipeline or a one-off rule with no stable ID.
+func NewPipelineRiskAnalyzer(rules ...RiskRule) *PipelineRiskAnalyzer {
+	return &PipelineRiskAnalyzer{rules: rules}
+}
+
+func (a *PipelineRiskAnalyzer) Analyze(grants *GrantSet) RiskReport {
+	if grants == nil {
+		return RiskReport{Level: RiskNone}
+	}
+	var factors []RiskFactor
+	for _, rule := range a.rules {
+		factors = append(factors, rule.Evaluate(grants)...)
+	}
+	return computeReport(factors)
+}
+
+// riskWeight is a factor's contribution to RiskReport.Score.
+func riskWeight(level RiskLevel) int {
+	switch level {
+	case RiskLow:
+		return 1
+	case RiskMedium:
+		return 3
+	case RiskHigh:
+		return 7
+	case RiskCritical:
+		return 15
+	default:
+		return 0
+	}
+}
+
+// computeReport builds a RiskReport from factors: Level is the highest
+// EffectiveLevel seen, either from a factor directly or from a fired
+// CombinationRule, and Score sums riskWeight(EffectiveLevel()) across
+// factors. Every RiskAnalyzer in this package builds its report through
+// this function so Score and Combinations behave identically regardless
+// of which one produced the factors.
+func computeReport(factors []RiskFactor) RiskReport {
+	report := RiskReport{Level: RiskNone, RiskFactors: factors}
+	for _, f := range factors {
+		level := f.EffectiveLevel()
+		if level > report.Level {
+			report.Level = level
+		}
+		report.Score += riskWeight(level)
+	}
+	report.Combinations = evaluateCombinations(factors)
+	for _, combo := range report.Combinations {
+		if combo.Level > report.Level {
+			report.Level = combo.Level
+		}
+	}
+	return report
+}
+
+// defaultBroadPatterns are the filesystem path fragments the built-in
+// filesystem rule treats as recursive/broad access; "**" covers the common
+// "everything under this directory" glob convention.
+var defaultBroadPatterns = []string{"**"}
+
+// defaultInterpreters are executable basenames the built-in exec rule treats
+// as a shell or scripting interpreter, which can run arbitrary code beyond
+// the literal command granted.
+var defaultInterpreters = []string{
+	"bash", "sh", "zsh", "ksh", "csh", "tcsh", "ash", "dash",
+	"python", "python3", "perl", "ruby", "node", "php", "lua",
+}
+
+// RuleDefinition registers one RiskRule under a stable ID, a description,
+// a nominal default RiskLevel, and a Builder that constructs it. Plugin
+// authors and hosts use this to add domain-specific checks (e.g.
+// Kubernetes secret paths, cloud-metadata IPs) or to reconfigure a
+// built-in rule's parameters, via RegisterRule, without forking the SDK.
+type RuleDefinition struct {
+	ID           string
+	Description  string
+	DefaultLevel RiskLevel
+	Builder      func() RiskRule
+}
+
+// RuleRegistry holds RuleDefinitions addressable by ID, in registration
+// order - modeled after gosec's RuleList.
+type RuleRegistry struct {
+	mu    sync.RWMutex
+	defs  map[string]RuleDefinition
+	order []string
+}
+
+// NewRuleRegistry returns an empty RuleRegistry.
+func NewRuleRegistry() *RuleRegistry {
+	return &RuleRegistry{defs: make(map[string]RuleDefinition)}
+}
+
+// Register adds def to the registry, or replaces the existing definition
+// with the same ID in place without disturbing registration order.
+func (r *RuleRegistry) Register(def RuleDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.defs[def.ID]; !exists {
+		r.order = append(r.order, def.ID)
+	}
+	r.defs[def.ID] = def
+}
+
+// Disable removes the definition with the given ID, if one is registered.
+func (r *RuleRegistry) Disable(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.defs[id]; !ok {
+		return
+	}
+	delete(r.defs, id)
+	for i, existing := range r.order {
+		if existing == id {
+			r.order = append(r.order[:i:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get returns the definition registered under id, if any.
+func (r *RuleRegistry) Get(id string) (RuleDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.defs[id]
+	return def, ok
+}
+
+// Definitions returns a snapshot of the registered definitions, in
+// registration order.
+func (r *RuleRegistry) Definitions() []RuleDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]RuleDefinition, 0, len(r.order))
+	for _, id := range r.order {
+		defs = append(defs, r.defs[id])
+	}
+	return defs
+}
+
+// defaultRuleRegistry holds the built-in Network/FS/Exec/Env/KV checks
+// that back NewSimpleRiskAnalyzer and a no-args NewRiskAnalyzer.
+// RegisterRule/DisableRule mutate it directly, so a host can add, replace,
+// or remove a rule once at startup and have it apply everywhere those are
+// used afterwards.
+var defaultRuleRegistry = NewRuleRegistry()
+
+func init() {
+	defaultRuleRegistry.Register(RuleDefinition{
+		ID: "NET001", Description: "Outbound network access", DefaultLevel: RiskMedium,
+		Builder: func() RiskRule { return &networkRiskRule{} },
+	})
+	defaultRuleRegistry.Register(RuleDefinition{
+		ID: "FS001", Description: "Filesystem read/write access", DefaultLevel: RiskMedium,
+		Builder: func() RiskRule { return &filesystemRiskRule{broadPatterns: defaultBroadPatterns} },
+	})
+	defaultRuleRegistry.Register(RuleDefinition{
+		ID: "EXEC001", Description: "Command execution", DefaultLevel: RiskMedium,
+		Builder: func() RiskRule { return &execRiskRule{interpreters: defaultInterpreters} },
+	})
+	defaultRuleRegistry.Register(RuleDefinition{
+		ID: "ENV001", Description: "Environment variable access", DefaultLevel: RiskLow,
+		Builder: func() RiskRule { return &environmentRiskRule{} },
+	})
+	defaultRuleRegistry.Register(RuleDefinition{
+		ID: "KV001", Description: "Key-value store access", DefaultLevel: RiskLow,
+		Builder: func() RiskRule { return &keyValueRiskRule{} },
+	})
+}
+
+// RegisterRule adds or replaces a RuleDefinition in the default registry
+// used by NewSimpleRiskAnalyzer and by NewRiskAnalyzer called with no
+// arguments. Use this to add a domain-specific check without forking the
+// SDK, or to replace a built-in rule's ID with a reconfigured Builder.
+func RegisterRule(def RuleDefinition) {
+	defaultRuleRegistry.Register(def)
+}
+
+// DisableRule removes a rule from the default registry by ID, so it no
+// longer runs in NewSimpleRiskAnalyzer or a no-args NewRiskAnalyzer.
+func DisableRule(id string) {
+	defaultRuleRegistry.Disable(id)
+}
+
+// NewRiskAnalyzer builds a RiskAnalyzer from rules. With no rules given, it
+// runs the default registry's rules - the same ones NewSimpleRiskAnalyzer
+// uses - so RegisterRule/DisableRule calls made at startup take effect.
+// Passing rules explicitly replaces the default set entirely; to keep the
+// built-ins and add to them, call RegisterRule beforehand instead of
+// passing the extra rule here.
+func NewRiskAnalyzer(rules ...RuleDefinition) RiskAnalyzer {
+	defs := rules
+	if len(defs) == 0 {
+		defs = defaultRuleRegistry.Definitions()
+	}
+	built := make([]RiskRule, 0, len(defs))
+	for _, def := range defs {
+		if def.Builder == nil {
+			continue
+		}
+		built = append(built, def.Builder())
+	}
+	return &PipelineRiskAnalyzer{rules: built}
+}
+
+// NewSimpleRiskAnalyzer returns the built-in rule pipeline registered in
+// the default registry - network, filesystem, exec, environment, and
+// key-value scoring. It's the preset most hosts want; call RegisterRule/
+// DisableRule beforehand, or NewRiskAnalyzer directly, to customize it.
+func NewSimpleRiskAnalyzer() RiskAnalyzer {
+	return NewRiskAnalyzer()
+}
+
+// WithBroadPatterns returns a RuleDefinition for the built-in filesystem
+// rule (ID "FS001") configured with patterns in place of
+// defaultBroadPatterns. Passing it to NewRiskAnalyzer replaces the whole
+// rule set with just this one; combine it with defaultRuleRegistry's other
+// definitions if you need the rest of the built-ins too.
+func WithBroadPatterns(patterns ...string) RuleDefinition {
+	return RuleDefinition{
+		ID: "FS001", Description: "Filesystem read/write access", DefaultLevel: RiskMedium,
+		Builder: func() RiskRule { return &filesystemRiskRule{broadPatterns: patterns} },
+	}
+}
+
+// WithInterpreters returns a RuleDefinition for the built-in exec rule (ID
+// "EXEC001") configured with interpreters in place of defaultInterpreters.
+func WithInterpreters(interpreters ...string) RuleDefinition {
+	return RuleDefinition{
+		ID: "EXEC001", Description: "Command execution", DefaultLevel: RiskMedium,
+		Builder: func() RiskRule { return &execRiskRule{interpreters: interpreters} },
+	}
+}
+
+// filesystemRiskRule scores FileSystemCapability rules. A path matching a
+// broadPatterns fragment (e.g. "/data/**" recursing under /data) always
+// scores Critical regardless of read/write; otherwise the level derives
+// from classifier's PathClass for that path crossed with the operation.
+type filesystemRiskRule struct {
+	broadPatterns []string
+	classifier    *PathClassifier
+}
+
+func (r filesystemRiskRule) Evaluate(grants *GrantSet) []RiskFactor {
+	if grants.FS == nil {
+		return nil
+	}
+	var factors []RiskFactor
+	for _, rule := range grants.FS.Rules {
+		factors = append(factors, r.evaluateRule(rule)...)
+	}
+	return factors
+}
+
+// evaluateRule scores a single FileSystemRule, split out from Evaluate so
+// NewPolicyRiskAnalyzer can apply path-prefix overrides per rule without
+// re-deriving a rule's paths from the formatted RiskFactor.Rule string.
+func (r filesystemRiskRule) evaluateRule(rule FileSystemRule) []RiskFactor {
+	var factors []RiskFactor
+	if len(rule.Write) > 0 {
+		level, ruleID := r.scoreOp("write", rule.Write)
+		factors = append(factors, RiskFactor{
+			Level: level, Description: "Filesystem write access",
+			Rule: fmt.Sprintf("FS Write: %v", rule.Write), RuleID: ruleID,
+		})
+	}
+	if len(rule.Read) > 0 {
+		level, ruleID := r.scoreOp("read", rule.Read)
+		factors = append(factors, RiskFactor{
+			Level: level, Description: "Filesystem read access",
+			Rule: fmt.Sprintf("FS Read: %v", rule.Read), RuleID: ruleID,
+		})
+	}
+	if hasTraversal(rule.Write) || hasTraversal(rule.Read) {
+		factors = append(factors, RiskFactor{
+			Level:       RiskHigh,
+			Description: `Path traversal pattern (".." ) in a granted path`,
+			Rule:        "FS Traversal", RuleID: "FS-Traversal",
+		})
+	}
+	return factors
+}
+
+// scoreOp scores every path in paths for op ("read" or "write") and
+// returns the highest-scoring path's level and RuleID, so one broad or
+// sensitive path dominates the rule's overall score the same way the
+// network rule's host loop does.
+func (r filesystemRiskRule) scoreOp(op string, paths []string) (RiskLevel, string) {
+	level, ruleID := RiskNone, ""
+	for _, p := range paths {
+		var pLevel RiskLevel
+		var pRuleID string
+		if r.isBroad([]string{p}) {
+			pLevel, pRuleID = RiskCritical, broadFSRuleID(op)
+		} else {
+			pLevel, pRuleID = classifiedFSLevel(op, r.classifierOrDefault().Classify(p))
+		}
+		if ruleID == "" || pLevel > level {
+			level, ruleID = pLevel, pRuleID
+		}
+	}
+	return level, ruleID
+}
+
+func (r filesystemRiskRule) classifierOrDefault() *PathClassifier {
+	if r.classifier != nil {
+		return r.classifier
+	}
+	return defaultPathClassifier
+}
+
+func (r filesystemRiskRule) isBroad(paths []string) bool {
+	for _, p := range paths {
+		for _, pattern := range r.broadPatterns {
+			if strings.Contains(p, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func broadFSRuleID(op string) string {
+	if op == "write" {
+		return "FS001-WriteBroad"
+	}
+	return "FS001-ReadBroad"
+}
+
+// classifiedFSLevel derives a RiskLevel from (op, class). Combinations not
+// named here - a write to Binaries or Unknown, a read of System, and so
+// on - fall back to the plain read/write defaults FS001-Read/FS001-Write
+// used before path classification existed.
+func classifiedFSLevel(op string, class PathClass) (RiskLevel, string) {
+	switch {
+	case op == "write" && class == PathClassSystem:
+		return RiskCritical, "FS-Write-System"
+	case op == "write" && class == PathClassUserSecrets:
+		return RiskCritical, "FS-Write-UserSecrets"
+	case op == "read" && class == PathClassUserSecrets:
+		return RiskHigh, "FS-Read-UserSecrets"
+	case op == "write" && class == PathClassWorkspace:
+		return RiskLow, "FS-Write-Workspace"
+	case op == "read" && class == PathClassEphemeral:
+		return RiskNone, "FS-Read-Ephemeral"
+	case op == "write":
+		return RiskHigh, "FS001-Write"
+	default:
+		return RiskMedium, "FS001-Read"
+	}
+}
+
+func hasTraversal(paths []string) bool {
+	for _, p := range paths {
+		if strings.Contains(p, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// execRiskRule scores ExecCapability commands, treating a command whose
+// basename matches one of interpreters - a shell or scripting runtime that
+// can run arbitrary code beyond the literal command granted - as higher
+// risk than a fixed, single-purpose binary.
+type execRiskRule struct {
+	interpreters []string
+}
+
+func (r execRiskRule) Evaluate(grants *GrantSet) []RiskFactor {
+	if grants.Exec == nil || len(grants.Exec.Commands) == 0 {
+		return nil
+	}
+	ruleStr := fmt.Sprintf("Exec: %v", grants.Exec.Commands)
+	if r.hasInterpreter(grants.Exec.Commands) {
+		return []RiskFactor{{
+			Level: RiskHigh, Description: "Shell or interpreter execution",
+			Rule: ruleStr, RuleID: "EXEC001-ArbitraryCommand",
+		}}
+	}
+	return []RiskFactor{{
+		Level: RiskMedium, Description: "Fixed command execution",
+		Rule: ruleStr, RuleID: "EXEC001-FixedCommand",
+	}}
+}
+
+func (r execRiskRule) hasInterpreter(commands []string) bool {
+	for _, cmd := range commands {
+		base := path.Base(cmd)
+		for _, interp := range r.interpreters {
+			if base == interp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// environmentRiskRule scores EnvironmentCapability variables, separating a
+// wildcard grant - every environment variable, which often includes
+// secrets - from a named allow-list.
+type environmentRiskRule struct{}
+
+func (environmentRiskRule) Evaluate(grants *GrantSet) []RiskFactor {
+	if grants.Env == nil || len(grants.Env.Variables) == 0 {
+		return nil
+	}
+	ruleStr := fmt.Sprintf("Env: %v", grants.Env.Variables)
+	for _, v := range grants.Env.Variables {
+		if v == "*" {
+			return []RiskFactor{{
+				Level: RiskHigh, Description: "Unrestricted environment variable access",
+				Rule: ruleStr, RuleID: "ENV001-Wildcard",
+			}}
+		}
+	}
+	return []RiskFactor{{
+		Level: RiskLow, Description: "Environment variable access",
+		Rule: ruleStr, RuleID: "ENV001-Scoped",
+	}}
+}
+
+// keyValueRiskRule scores KeyValueCapability rules. The heuristic analyzer
+// this superseded didn't score KV access at all, silently reporting
+// RiskNone for a plugin with write access to the shared store.
+type keyValueRiskRule struct{}
+
+func (keyValueRiskRule) Evaluate(grants *GrantSet) []RiskFactor {
+	if grants.KV == nil {
+		return nil
+	}
+	var factors []RiskFactor
+	for _, rule := range grants.KV.Rules {
+		ruleStr := fmt.Sprintf("KV %s: %v", rule.Operation, rule.Keys)
+		switch rule.Operation {
+		case "write", "readwrite":
+			factors = append(factors, RiskFactor{
+				Level: RiskMedium, Description: "Key-value write access",
+				Rule: ruleStr, RuleID: "KV001-Write",
+			})
+		default:
+			factors = append(factors, RiskFactor{
+				Level: RiskLow, Description: "Key-value read access",
+				Rule: ruleStr, RuleID: "KV001-Read",
+			})
+		}
+	}
+	return factors
+}
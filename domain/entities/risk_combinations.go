@@ -0,0 +1,160 @@
+package entities
+
+import (
+	"strings"
+	"sync"
+)
+
+// RiskCombination is a fired combinatorial escalation: RiskFactors whose
+// RuleIDs co-occurred in the same report and, together, are more
+// dangerous than any of them alone (see CombinationRule).
+type RiskCombination struct {
+	ID          string
+	Description string
+	Level       RiskLevel
+	// RuleIDs are the specific factor RuleIDs that satisfied this
+	// combination, one per CombinationRule.Requires entry, in order.
+	RuleIDs []string
+}
+
+// CombinationRule fires a RiskCombination when every pattern in Requires
+// matches at least one RiskFactor.RuleID produced for the same GrantSet.
+// A pattern is a RuleID prefix with an optional trailing "*" - purely
+// cosmetic, stripped before matching - so "NET001-WildcardHost" matches
+// only that exact ID, while "EXEC001-ArbitraryCommand" and "NET*" match
+// any RuleID starting with "EXEC001-ArbitraryCommand" or "NET"
+// respectively.
+type CombinationRule struct {
+	ID          string
+	Description string
+	Level       RiskLevel
+	Requires    []string
+}
+
+// CombinationRegistry holds CombinationRules addressable by ID, in
+// registration order - the same Register/Disable/Definitions shape as
+// RuleRegistry, so combination rules are added and removed the same way
+// individual rules are.
+type CombinationRegistry struct {
+	mu    sync.RWMutex
+	defs  map[string]CombinationRule
+	order []string
+}
+
+// NewCombinationRegistry returns an empty CombinationRegistry.
+func NewCombinationRegistry() *CombinationRegistry {
+	return &CombinationRegistry{defs: make(map[string]CombinationRule)}
+}
+
+// Register adds rule to the registry, or replaces the existing rule with
+// the same ID in place without disturbing registration order.
+func (r *CombinationRegistry) Register(rule CombinationRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.defs[rule.ID]; !exists {
+		r.order = append(r.order, rule.ID)
+	}
+	r.defs[rule.ID] = rule
+}
+
+// Disable removes the rule with the given ID, if one is registered.
+func (r *CombinationRegistry) Disable(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.defs[id]; !ok {
+		return
+	}
+	delete(r.defs, id)
+	for i, existing := range r.order {
+		if existing == id {
+			r.order = append(r.order[:i:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Definitions returns a snapshot of the registered rules, in registration
+// order.
+func (r *CombinationRegistry) Definitions() []CombinationRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]CombinationRule, 0, len(r.order))
+	for _, id := range r.order {
+		defs = append(defs, r.defs[id])
+	}
+	return defs
+}
+
+// defaultCombinationRegistry holds the built-in escalation rules that
+// every RiskAnalyzer in this package checks via computeReport.
+var defaultCombinationRegistry = NewCombinationRegistry()
+
+func init() {
+	defaultCombinationRegistry.Register(CombinationRule{
+		ID:          "Combo-Exfiltration",
+		Description: "Unrestricted network access plus system-directory write access",
+		Level:       RiskCritical,
+		Requires:    []string{"NET001-WildcardHost", "FS-Write-System"},
+	})
+	defaultCombinationRegistry.Register(CombinationRule{
+		ID:          "Combo-RemoteShell",
+		Description: "Shell or interpreter execution plus any network access",
+		Level:       RiskCritical,
+		Requires:    []string{"EXEC001-ArbitraryCommand", "NET*"},
+	})
+	defaultCombinationRegistry.Register(CombinationRule{
+		ID:          "Combo-CredentialTheft",
+		Description: "User credential file read access plus any network access",
+		Level:       RiskCritical,
+		Requires:    []string{"FS-Read-UserSecrets", "NET*"},
+	})
+}
+
+// RegisterCombination adds or replaces a CombinationRule in the default
+// registry, analogous to RegisterRule for individual rules.
+func RegisterCombination(rule CombinationRule) {
+	defaultCombinationRegistry.Register(rule)
+}
+
+// DisableCombination removes a combination rule from the default registry
+// by ID, analogous to DisableRule.
+func DisableCombination(id string) {
+	defaultCombinationRegistry.Disable(id)
+}
+
+func combinationPatternMatches(ruleID, pattern string) bool {
+	pattern = strings.TrimSuffix(pattern, "*")
+	return pattern != "" && strings.HasPrefix(ruleID, pattern)
+}
+
+// evaluateCombinations runs the default registry's CombinationRules
+// against factors and returns every one whose Requires patterns all
+// matched at least one factor.
+func evaluateCombinations(factors []RiskFactor) []RiskCombination {
+	var fired []RiskCombination
+	for _, rule := range defaultCombinationRegistry.Definitions() {
+		matchedIDs := make([]string, 0, len(rule.Requires))
+		satisfied := true
+		for _, pattern := range rule.Requires {
+			matched := ""
+			for _, f := range factors {
+				if combinationPatternMatches(f.RuleID, pattern) {
+					matched = f.RuleID
+					break
+				}
+			}
+			if matched == "" {
+				satisfied = false
+				break
+			}
+			matchedIDs = append(matchedIDs, matched)
+		}
+		if satisfied {
+			fired = append(fired, RiskCombination{
+				ID: rule.ID, Description: rule.Description, Level: rule.Level,
+				RuleIDs: matchedIDs,
+			})
+		}
+	}
+	return fired
+}
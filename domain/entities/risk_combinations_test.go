@@ -0,0 +1,97 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskReport_ScoreSumsFactorWeights(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		Env: &entities.EnvironmentCapability{Variables: []string{"HOME"}},
+		KV:  &entities.KeyValueCapability{Rules: []entities.KeyValueRule{{Keys: []string{"a"}, Operation: "write"}}},
+	}
+	report := assessor.Analyze(g)
+	// ENV001-Scoped is Low (weight 1), KV001-Write is Medium (weight 3).
+	assert.Equal(t, 4, report.Score)
+}
+
+func TestRiskReport_RemoteShellCombinationFires(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		Exec:    &entities.ExecCapability{Commands: []string{"/bin/bash"}},
+		Network: &entities.NetworkCapability{Rules: []entities.NetworkRule{{Hosts: []string{"example.com"}, Ports: []string{"443"}}}},
+	}
+	report := assessor.Analyze(g)
+	require.Len(t, report.Combinations, 1)
+	assert.Equal(t, "Combo-RemoteShell", report.Combinations[0].ID)
+	assert.Equal(t, entities.RiskCritical, report.Level)
+}
+
+func TestRiskReport_RemoteShellCombinationDoesNotFireForFixedCommand(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		Exec:    &entities.ExecCapability{Commands: []string{"/bin/ls"}},
+		Network: &entities.NetworkCapability{Rules: []entities.NetworkRule{{Hosts: []string{"example.com"}, Ports: []string{"443"}}}},
+	}
+	report := assessor.Analyze(g)
+	assert.NotContains(t, comboIDs(report.Combinations), "Combo-RemoteShell")
+}
+
+func TestRiskReport_CombinationDoesNotFireWithoutBothParts(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+	g := &entities.GrantSet{
+		Exec: &entities.ExecCapability{Commands: []string{"/bin/bash"}},
+	}
+	report := assessor.Analyze(g)
+	assert.Empty(t, report.Combinations)
+}
+
+func TestRiskReport_CredentialTheftAndExfiltrationCombinations(t *testing.T) {
+	assessor := entities.NewSimpleRiskAnalyzer()
+
+	theft := &entities.GrantSet{
+		FS:      &entities.FileSystemCapability{Rules: []entities.FileSystemRule{{Read: []string{"~/.aws/credentials"}}}},
+		Network: &entities.NetworkCapability{Rules: []entities.NetworkRule{{Hosts: []string{"example.com"}, Ports: []string{"443"}}}},
+	}
+	theftReport := assessor.Analyze(theft)
+	ids := comboIDs(theftReport.Combinations)
+	assert.Contains(t, ids, "Combo-CredentialTheft")
+
+	exfil := &entities.GrantSet{
+		FS:      &entities.FileSystemCapability{Rules: []entities.FileSystemRule{{Write: []string{"/etc/passwd"}}}},
+		Network: &entities.NetworkCapability{Rules: []entities.NetworkRule{{Hosts: []string{"*"}, Ports: []string{"443"}}}},
+	}
+	exfilReport := assessor.Analyze(exfil)
+	assert.Contains(t, comboIDs(exfilReport.Combinations), "Combo-Exfiltration")
+}
+
+func comboIDs(combos []entities.RiskCombination) []string {
+	ids := make([]string, len(combos))
+	for i, c := range combos {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestRegisterCombination_AndDisableCombination(t *testing.T) {
+	entities.RegisterCombination(entities.CombinationRule{
+		ID: "Combo-Test", Level: entities.RiskCritical,
+		Requires: []string{"ENV001-Wildcard", "KV001-Write"},
+	})
+	t.Cleanup(func() { entities.DisableCombination("Combo-Test") })
+
+	g := &entities.GrantSet{
+		Env: &entities.EnvironmentCapability{Variables: []string{"*"}},
+		KV:  &entities.KeyValueCapability{Rules: []entities.KeyValueRule{{Keys: []string{"a"}, Operation: "write"}}},
+	}
+	report := entities.NewSimpleRiskAnalyzer().Analyze(g)
+	assert.Contains(t, comboIDs(report.Combinations), "Combo-Test")
+
+	entities.DisableCombination("Combo-Test")
+	report2 := entities.NewSimpleRiskAnalyzer().Analyze(g)
+	assert.NotContains(t, comboIDs(report2.Combinations), "Combo-Test")
+}
@@ -0,0 +1,315 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strings"
+)
+
+// RiskPolicy customizes a RiskAnalyzer's severities and its Network/FS/Exec
+// handling without recompiling - see NewPolicyRiskAnalyzer.
+//
+// LoadRiskPolicy decodes JSON, not full YAML: this repo has no YAML decoder
+// vendored, and JSON is a valid subset of YAML 1.2, so a policy file
+// written as JSON loads correctly here and also parses with whatever YAML
+// parser a host uses downstream if it prefers to author the file as YAML.
+type RiskPolicy struct {
+	// Severities overrides a rule's effective RiskLevel by RuleID (e.g.
+	// {"NET001-WildcardHost": "low"}). Applied only to factors no more
+	// specific override (host, path, or exec-allow policy) already fired
+	// for.
+	Severities map[string]string `json:"severities,omitempty" yaml:"severities,omitempty"`
+
+	// Hosts forces a RiskLevel for any Network rule admitting a host
+	// matched by an Allow or Deny entry; Deny takes precedence over Allow.
+	Hosts HostPolicy `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+
+	// Paths forces a RiskLevel for an FS rule's read or write access when
+	// one of its paths matches Prefix, in order; the first match wins.
+	Paths []PathPolicyRule `json:"paths,omitempty" yaml:"paths,omitempty"`
+
+	// ExecAllow downgrades the Exec rule's factors to RiskMedium when
+	// every command in ExecCapability.Commands matches an entry here
+	// (exact match or basename match).
+	ExecAllow []string `json:"exec_allow,omitempty" yaml:"exec_allow,omitempty"`
+}
+
+// HostPolicy is RiskPolicy's host/CIDR allow and deny lists.
+type HostPolicy struct {
+	Allow []HostRule `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []HostRule `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// HostRule forces Level for a Network rule admitting Host, which may be an
+// exact hostname, a CIDR, or a "*.example.com" wildcard suffix.
+type HostRule struct {
+	Host  string `json:"host" yaml:"host"`
+	Level string `json:"level" yaml:"level"`
+}
+
+// PathPolicyRule forces Level for an FS rule's Op ("read" or "write") when
+// one of its paths has Prefix (a trailing "**" is trimmed before matching,
+// so "/etc/**" becomes the prefix "/etc/" and matches "/etc/passwd").
+type PathPolicyRule struct {
+	Op     string `json:"op" yaml:"op"`
+	Prefix string `json:"prefix" yaml:"prefix"`
+	Level  string `json:"level" yaml:"level"`
+}
+
+// LoadRiskPolicy decodes a RiskPolicy from r. See RiskPolicy's doc comment
+// for the JSON/YAML caveat.
+func LoadRiskPolicy(r io.Reader) (*RiskPolicy, error) {
+	var policy RiskPolicy
+	if err := json.NewDecoder(r).Decode(&policy); err != nil {
+		return nil, fmt.Errorf("entities: parse risk policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// ParseRiskLevel parses a policy file's level string ("low", "HIGH", ...)
+// into a RiskLevel.
+func ParseRiskLevel(s string) (RiskLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none":
+		return RiskNone, nil
+	case "low":
+		return RiskLow, nil
+	case "medium":
+		return RiskMedium, nil
+	case "high":
+		return RiskHigh, nil
+	case "critical":
+		return RiskCritical, nil
+	default:
+		return RiskNone, fmt.Errorf("entities: unknown risk level %q", s)
+	}
+}
+
+// PolicyRiskAnalyzer runs a GrantSet through the default Network/FS/Exec/
+// Env/KV rules, then applies a RiskPolicy on top: host and path overrides
+// take precedence over a blanket per-rule Severities override, and every
+// factor's PolicySource records which (if any) override applied.
+type PolicyRiskAnalyzer struct {
+	policy *RiskPolicy
+}
+
+// NewPolicyRiskAnalyzer builds a RiskAnalyzer that scores the same way
+// NewSimpleRiskAnalyzer does, then applies policy on top. A nil policy
+// behaves exactly like NewSimpleRiskAnalyzer, except every factor still
+// gets PolicySource "default".
+func NewPolicyRiskAnalyzer(policy *RiskPolicy) RiskAnalyzer {
+	if policy == nil {
+		policy = &RiskPolicy{}
+	}
+	return &PolicyRiskAnalyzer{policy: policy}
+}
+
+func (a *PolicyRiskAnalyzer) Analyze(grants *GrantSet) RiskReport {
+	if grants == nil {
+		return RiskReport{Level: RiskNone}
+	}
+
+	var factors []RiskFactor
+	factors = append(factors, a.networkFactors(grants)...)
+	factors = append(factors, a.filesystemFactors(grants)...)
+	factors = append(factors, a.execFactors(grants)...)
+	factors = append(factors, taggedDefault(environmentRiskRule{}.Evaluate(grants))...)
+	factors = append(factors, taggedDefault(keyValueRiskRule{}.Evaluate(grants))...)
+
+	a.applySeverityOverrides(factors)
+
+	return computeReport(factors)
+}
+
+// taggedDefault marks every factor's PolicySource "default" - used for
+// rules RiskPolicy has no dedicated override section for (Env, KV), so
+// they still participate in the Severities fallback.
+func taggedDefault(factors []RiskFactor) []RiskFactor {
+	for i := range factors {
+		factors[i].PolicySource = "default"
+	}
+	return factors
+}
+
+func (a *PolicyRiskAnalyzer) networkFactors(grants *GrantSet) []RiskFactor {
+	if grants.Network == nil {
+		return nil
+	}
+	var factors []RiskFactor
+	for _, rule := range grants.Network.Rules {
+		matcher := ParseNetworkRule(rule)
+
+		level, ruleID := RiskNone, ""
+		for _, h := range matcher.Hosts {
+			if hLevel, hRuleID := hostRiskLevel(h); ruleID == "" || hLevel > level {
+				level, ruleID = hLevel, hRuleID
+			}
+		}
+		if ruleID == "" {
+			level, ruleID = RiskMedium, "NET001-ScopedHost"
+		}
+		portBump := matcher.HasSensitivePort()
+		if portBump {
+			level = bumpLevel(level)
+		}
+
+		factor := RiskFactor{
+			Level: level, Description: networkFactorDescription(ruleID, portBump),
+			Rule: fmt.Sprintf("Network: %v:%v", rule.Hosts, rule.Ports), RuleID: ruleID,
+			PolicySource: "default",
+		}
+		if forced, source, ok := a.policy.Hosts.forcedLevel(rule.Hosts); ok {
+			forced := forced
+			factor.Severity = &forced
+			factor.PolicySource = source
+		}
+		factors = append(factors, factor)
+	}
+	return factors
+}
+
+func (a *PolicyRiskAnalyzer) filesystemFactors(grants *GrantSet) []RiskFactor {
+	if grants.FS == nil {
+		return nil
+	}
+	rule := filesystemRiskRule{broadPatterns: defaultBroadPatterns}
+	var factors []RiskFactor
+	for _, fsRule := range grants.FS.Rules {
+		for _, f := range rule.evaluateRule(fsRule) {
+			f.PolicySource = "default"
+			if f.RuleID == "FS-Traversal" {
+				factors = append(factors, f)
+				continue
+			}
+			op, paths := "read", fsRule.Read
+			if strings.Contains(f.RuleID, "Write") {
+				op, paths = "write", fsRule.Write
+			}
+			if level, source, ok := a.policy.pathOverride(op, paths); ok {
+				level := level
+				f.Severity = &level
+				f.PolicySource = source
+			}
+			factors = append(factors, f)
+		}
+	}
+	return factors
+}
+
+func (a *PolicyRiskAnalyzer) execFactors(grants *GrantSet) []RiskFactor {
+	if grants.Exec == nil || len(grants.Exec.Commands) == 0 {
+		return nil
+	}
+	rule := execRiskRule{interpreters: defaultInterpreters}
+	factors := taggedDefault(rule.Evaluate(grants))
+
+	if len(a.policy.ExecAllow) > 0 && allCommandsAllowed(grants.Exec.Commands, a.policy.ExecAllow) {
+		for i := range factors {
+			medium := RiskMedium
+			factors[i].Severity = &medium
+			factors[i].PolicySource = "policy:exec_allow"
+		}
+	}
+	return factors
+}
+
+func allCommandsAllowed(commands, allow []string) bool {
+	for _, cmd := range commands {
+		matched := false
+		for _, entry := range allow {
+			if cmd == entry || path.Base(cmd) == entry {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *PolicyRiskAnalyzer) applySeverityOverrides(factors []RiskFactor) {
+	if len(a.policy.Severities) == 0 {
+		return
+	}
+	for i := range factors {
+		f := &factors[i]
+		if f.PolicySource != "default" {
+			continue // a more specific override already applied
+		}
+		raw, ok := a.policy.Severities[f.RuleID]
+		if !ok {
+			continue
+		}
+		level, err := ParseRiskLevel(raw)
+		if err != nil {
+			continue
+		}
+		f.Severity = &level
+		f.PolicySource = "policy:severities"
+	}
+}
+
+// forcedLevel checks hosts against Deny first, so an operator-configured
+// deny always wins over Allow, then against Allow.
+func (p HostPolicy) forcedLevel(hosts []string) (RiskLevel, string, bool) {
+	if level, ok := matchHostRules(hosts, p.Deny); ok {
+		return level, "policy:hosts.deny", true
+	}
+	if level, ok := matchHostRules(hosts, p.Allow); ok {
+		return level, "policy:hosts.allow", true
+	}
+	return RiskNone, "", false
+}
+
+func matchHostRules(hosts []string, rules []HostRule) (RiskLevel, bool) {
+	for _, host := range hosts {
+		for _, rule := range rules {
+			if !hostMatchesPolicyEntry(host, rule.Host) {
+				continue
+			}
+			if level, err := ParseRiskLevel(rule.Level); err == nil {
+				return level, true
+			}
+		}
+	}
+	return RiskNone, false
+}
+
+func hostMatchesPolicyEntry(host, entry string) bool {
+	if host == entry {
+		return true
+	}
+	if _, cidr, err := net.ParseCIDR(entry); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			return cidr.Contains(ip)
+		}
+	}
+	if suffix, ok := strings.CutPrefix(entry, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix) || host == suffix
+	}
+	return false
+}
+
+// pathOverride returns the first Paths rule (in order) whose Op matches op
+// and whose Prefix is a prefix of any entry in paths.
+func (p *RiskPolicy) pathOverride(op string, paths []string) (RiskLevel, string, bool) {
+	for _, rule := range p.Paths {
+		if !strings.EqualFold(rule.Op, op) {
+			continue
+		}
+		prefix := strings.TrimSuffix(rule.Prefix, "**")
+		for _, candidate := range paths {
+			if strings.HasPrefix(candidate, prefix) {
+				if level, err := ParseRiskLevel(rule.Level); err == nil {
+					return level, "policy:paths", true
+				}
+			}
+		}
+	}
+	return RiskNone, "", false
+}
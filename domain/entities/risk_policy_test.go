@@ -0,0 +1,134 @@
+package entities_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRiskPolicy_ParsesJSON(t *testing.T) {
+	r := strings.NewReader(`{
+		"severities": {"NET001-WildcardHost": "low"},
+		"hosts": {"deny": [{"host": "169.254.169.254", "level": "critical"}]},
+		"paths": [{"op": "write", "prefix": "/tmp/**", "level": "low"}],
+		"exec_allow": ["ls"]
+	}`)
+	policy, err := entities.LoadRiskPolicy(r)
+	require.NoError(t, err)
+	assert.Equal(t, "low", policy.Severities["NET001-WildcardHost"])
+	assert.Len(t, policy.Hosts.Deny, 1)
+	assert.Equal(t, []string{"ls"}, policy.ExecAllow)
+}
+
+func TestParseRiskLevel(t *testing.T) {
+	level, err := entities.ParseRiskLevel("HIGH")
+	require.NoError(t, err)
+	assert.Equal(t, entities.RiskHigh, level)
+
+	_, err = entities.ParseRiskLevel("extreme")
+	assert.Error(t, err)
+}
+
+func TestPolicyRiskAnalyzer_SeverityOverride(t *testing.T) {
+	g := &entities.GrantSet{
+		Network: &entities.NetworkCapability{
+			Rules: []entities.NetworkRule{{Hosts: []string{"*"}, Ports: []string{"443"}}},
+		},
+	}
+	policy := &entities.RiskPolicy{Severities: map[string]string{"NET001-WildcardHost": "low"}}
+	report := entities.NewPolicyRiskAnalyzer(policy).Analyze(g)
+	assert.Equal(t, entities.RiskLow, report.Level)
+	require.Len(t, report.RiskFactors, 1)
+	assert.Equal(t, "policy:severities", report.RiskFactors[0].PolicySource)
+}
+
+func TestPolicyRiskAnalyzer_HostDenyWinsOverAllow(t *testing.T) {
+	g := &entities.GrantSet{
+		Network: &entities.NetworkCapability{
+			Rules: []entities.NetworkRule{{Hosts: []string{"169.254.169.254"}, Ports: []string{"80"}}},
+		},
+	}
+	policy := &entities.RiskPolicy{
+		Hosts: entities.HostPolicy{
+			Allow: []entities.HostRule{{Host: "169.254.169.254", Level: "low"}},
+			Deny:  []entities.HostRule{{Host: "169.254.169.254", Level: "critical"}},
+		},
+	}
+	report := entities.NewPolicyRiskAnalyzer(policy).Analyze(g)
+	assert.Equal(t, entities.RiskCritical, report.Level)
+	assert.Equal(t, "policy:hosts.deny", report.RiskFactors[0].PolicySource)
+}
+
+func TestPolicyRiskAnalyzer_HostCIDRAndWildcardMatching(t *testing.T) {
+	g := &entities.GrantSet{
+		Network: &entities.NetworkCapability{
+			Rules: []entities.NetworkRule{{Hosts: []string{"10.0.0.5"}, Ports: []string{"443"}}},
+		},
+	}
+	policy := &entities.RiskPolicy{
+		Hosts: entities.HostPolicy{Allow: []entities.HostRule{{Host: "10.0.0.0/8", Level: "none"}}},
+	}
+	report := entities.NewPolicyRiskAnalyzer(policy).Analyze(g)
+	assert.Equal(t, entities.RiskNone, report.Level)
+	assert.Equal(t, "policy:hosts.allow", report.RiskFactors[0].PolicySource)
+
+	g2 := &entities.GrantSet{
+		Network: &entities.NetworkCapability{
+			Rules: []entities.NetworkRule{{Hosts: []string{"api.example.com"}, Ports: []string{"443"}}},
+		},
+	}
+	policy2 := &entities.RiskPolicy{
+		Hosts: entities.HostPolicy{Allow: []entities.HostRule{{Host: "*.example.com", Level: "low"}}},
+	}
+	report2 := entities.NewPolicyRiskAnalyzer(policy2).Analyze(g2)
+	assert.Equal(t, entities.RiskLow, report2.Level)
+}
+
+func TestPolicyRiskAnalyzer_PathPrefixOverride(t *testing.T) {
+	g := &entities.GrantSet{
+		FS: &entities.FileSystemCapability{
+			Rules: []entities.FileSystemRule{
+				{Read: []string{"/etc/passwd"}},
+				{Write: []string{"/tmp/scratch.txt"}},
+			},
+		},
+	}
+	policy := &entities.RiskPolicy{
+		Paths: []entities.PathPolicyRule{
+			{Op: "read", Prefix: "/etc/**", Level: "critical"},
+			{Op: "write", Prefix: "/tmp/**", Level: "low"},
+		},
+	}
+	report := entities.NewPolicyRiskAnalyzer(policy).Analyze(g)
+	assert.Equal(t, entities.RiskCritical, report.Level)
+	require.Len(t, report.RiskFactors, 2)
+	for _, f := range report.RiskFactors {
+		assert.Equal(t, "policy:paths", f.PolicySource)
+	}
+}
+
+func TestPolicyRiskAnalyzer_ExecAllowList(t *testing.T) {
+	allowed := &entities.GrantSet{Exec: &entities.ExecCapability{Commands: []string{"/usr/bin/ls"}}}
+	policy := &entities.RiskPolicy{ExecAllow: []string{"ls"}}
+	report := entities.NewPolicyRiskAnalyzer(policy).Analyze(allowed)
+	assert.Equal(t, entities.RiskMedium, report.Level)
+	assert.Equal(t, "policy:exec_allow", report.RiskFactors[0].PolicySource)
+
+	notAllowed := &entities.GrantSet{Exec: &entities.ExecCapability{Commands: []string{"/bin/bash"}}}
+	report2 := entities.NewPolicyRiskAnalyzer(policy).Analyze(notAllowed)
+	assert.Equal(t, entities.RiskHigh, report2.Level)
+	assert.Equal(t, "default", report2.RiskFactors[0].PolicySource)
+}
+
+func TestPolicyRiskAnalyzer_NilPolicyMatchesDefault(t *testing.T) {
+	g := &entities.GrantSet{
+		Exec: &entities.ExecCapability{Commands: []string{"/bin/bash"}},
+	}
+	defaultReport := entities.NewSimpleRiskAnalyzer().Analyze(g)
+	policyReport := entities.NewPolicyRiskAnalyzer(nil).Analyze(g)
+	assert.Equal(t, defaultReport.Level, policyReport.Level)
+	assert.Equal(t, "default", policyReport.RiskFactors[0].PolicySource)
+}
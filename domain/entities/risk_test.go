@@ -16,7 +16,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 		assert.Equal(t, entities.RiskNone, report.Level)
 	})
 
-	t.Run("Specific read access is Low risk", func(t *testing.T) {
+	t.Run("Read of an ephemeral path is None risk", func(t *testing.T) {
 		g := &entities.GrantSet{
 			FS: &entities.FileSystemCapability{
 				Rules: []entities.FileSystemRule{
@@ -25,6 +25,18 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 			},
 		}
 		report := assessor.Analyze(g)
+		assert.Equal(t, entities.RiskNone, report.Level)
+	})
+
+	t.Run("Specific read access is Medium risk", func(t *testing.T) {
+		g := &entities.GrantSet{
+			FS: &entities.FileSystemCapability{
+				Rules: []entities.FileSystemRule{
+					{Read: []string{"/data/file.txt"}},
+				},
+			},
+		}
+		report := assessor.Analyze(g)
 		assert.Equal(t, entities.RiskMedium, report.Level)
 	})
 
@@ -40,7 +52,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 		assert.Equal(t, entities.RiskHigh, report.Level)
 	})
 
-	t.Run("Recursive filesystem access is High risk", func(t *testing.T) {
+	t.Run("Recursive filesystem access is Critical risk", func(t *testing.T) {
 		g := &entities.GrantSet{
 			FS: &entities.FileSystemCapability{
 				Rules: []entities.FileSystemRule{
@@ -49,8 +61,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 			},
 		}
 		report := assessor.Analyze(g)
-		// SimpleRisk Analyzer treats all reads as Medium, doesn't check for **
-		assert.Equal(t, entities.RiskMedium, report.Level)
+		assert.Equal(t, entities.RiskCritical, report.Level)
 	})
 
 	t.Run("Exec with safe command is Medium risk", func(t *testing.T) {
@@ -60,7 +71,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 			},
 		}
 		report := assessor.Analyze(g)
-		assert.Equal(t, entities.RiskCritical, report.Level)
+		assert.Equal(t, entities.RiskMedium, report.Level)
 	})
 
 	t.Run("Exec with shell is High risk", func(t *testing.T) {
@@ -70,7 +81,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 			},
 		}
 		report := assessor.Analyze(g)
-		assert.Equal(t, entities.RiskCritical, report.Level)
+		assert.Equal(t, entities.RiskHigh, report.Level)
 	})
 
 	t.Run("All Network is High risk", func(t *testing.T) {
@@ -104,7 +115,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 			},
 		}
 		report := assessor.Analyze(g)
-		assert.Equal(t, entities.RiskLow, report.Level)
+		assert.Equal(t, entities.RiskHigh, report.Level)
 	})
 
 	t.Run("KV Write is Medium risk", func(t *testing.T) {
@@ -116,8 +127,7 @@ func TestRiskAssessor_AssessGrantSet(t *testing.T) {
 			},
 		}
 		report := assessor.Analyze(g)
-		// SimpleRiskAnalyzer doesn't check KV capabilities
-		assert.Equal(t, entities.RiskNone, report.Level)
+		assert.Equal(t, entities.RiskMedium, report.Level)
 	})
 }
 
@@ -147,9 +157,6 @@ func TestRiskAssessor_DescribeRisks(t *testing.T) {
 }
 
 func TestRiskAssessor_WithCustomBroadPatterns(t *testing.T) {
-	// Test that custom broad patterns work
-	assessor := entities.NewSimpleRiskAnalyzer()
-
 	g := &entities.GrantSet{
 		FS: &entities.FileSystemCapability{
 			Rules: []entities.FileSystemRule{
@@ -158,9 +165,49 @@ func TestRiskAssessor_WithCustomBroadPatterns(t *testing.T) {
 		},
 	}
 
+	t.Run("default broad pattern catches **", func(t *testing.T) {
+		assessor := entities.NewSimpleRiskAnalyzer()
+		report := assessor.Analyze(g)
+		assert.Equal(t, entities.RiskCritical, report.Level)
+	})
+
+	t.Run("custom broad pattern catches /custom prefix instead", func(t *testing.T) {
+		assessor := entities.NewRiskAnalyzer(entities.WithBroadPatterns("/custom"))
+		report := assessor.Analyze(g)
+		assert.Equal(t, entities.RiskCritical, report.Level)
+	})
+
+	t.Run("custom broad pattern that doesn't match stays at the narrow level", func(t *testing.T) {
+		assessor := entities.NewRiskAnalyzer(entities.WithBroadPatterns("/other"))
+		report := assessor.Analyze(g)
+		assert.Equal(t, entities.RiskMedium, report.Level)
+	})
+}
+
+func TestRiskAssessor_WithInterpreters(t *testing.T) {
+	g := &entities.GrantSet{
+		Exec: &entities.ExecCapability{
+			Commands: []string{"/usr/local/bin/mytool"},
+		},
+	}
+
+	assessor := entities.NewRiskAnalyzer(entities.WithInterpreters("mytool"))
+	report := assessor.Analyze(g)
+	assert.Equal(t, entities.RiskHigh, report.Level)
+}
+
+func TestRiskAssessor_WithRules(t *testing.T) {
+	assessor := entities.NewPipelineRiskAnalyzer()
+
+	g := &entities.GrantSet{
+		Exec: &entities.ExecCapability{
+			Commands: []string{"/bin/bash"},
+		},
+	}
+
 	report := assessor.Analyze(g)
-	// SimpleRiskAnalyzer treats all FS reads as Medium
-	assert.Equal(t, entities.RiskMedium, report.Level)
+	assert.Equal(t, entities.RiskNone, report.Level)
+	assert.Empty(t, report.RiskFactors)
 }
 
 func TestRisk_String(t *testing.T) {
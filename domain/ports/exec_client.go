@@ -0,0 +1,56 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ExecClient defines the interface for running external commands.
+// Infrastructure adapters implement this to provide exec functionality, so
+// capability checks and output limits stay enforced by the host rather than
+// a plugin shelling out directly.
+type ExecClient interface {
+	// Exec runs req to completion and returns its buffered output.
+	Exec(ctx context.Context, req ExecRequest) (*ExecResult, error)
+
+	// ExecStream runs req and returns its output as it's produced, for
+	// commands whose output is too large or long-lived to buffer in full
+	// (tailing logs, traceroute, long-running probes).
+	ExecStream(ctx context.Context, req ExecRequest) (ExecStream, error)
+}
+
+// ExecRequest describes a command to run.
+type ExecRequest struct {
+	Command string
+	Args    []string
+	Dir     string
+	Env     []string
+	Timeout time.Duration
+}
+
+// ExecResult is the buffered result of a completed Exec call.
+type ExecResult struct {
+	Stdout    []byte
+	Stderr    []byte
+	ExitCode  int
+	Duration  time.Duration
+	IsTimeout bool
+}
+
+// ExecStream is a running command's output, delivered as it's produced
+// rather than buffered to completion. Existing callers of Exec are
+// unaffected; this is purely an additional, opt-in API.
+type ExecStream interface {
+	// Stdout returns a reader for the command's standard output. Reads
+	// block until more data is available or the command exits.
+	Stdout() io.Reader
+
+	// Stderr returns a reader for the command's standard error.
+	Stderr() io.Reader
+
+	// Wait blocks until the command exits and returns its exit code. It
+	// returns an error if the command couldn't be run or was denied by a
+	// capability check (e.g. max_output_bytes/max_chunk_bytes exceeded).
+	Wait() (int, error)
+}
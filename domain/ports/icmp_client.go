@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// ICMPClient defines the interface for ICMP reachability probes.
+// Infrastructure adapters implement this to provide ping-style functionality
+// without a plugin having to exec the `ping` binary, which cuts across the
+// capability model awkwardly.
+type ICMPClient interface {
+	// Probe sends Count ICMP echo requests to host and reports per-packet
+	// and aggregate round-trip statistics.
+	Probe(ctx context.Context, req ICMPProbeRequest) (*ICMPProbeResult, error)
+}
+
+// ICMPProbeRequest describes a single ICMP probe run.
+type ICMPProbeRequest struct {
+	Host              string
+	Count             int
+	Interval          time.Duration
+	PayloadSize       int
+	Timeout           time.Duration
+	PreferIPv6        bool
+	DoNotFragment     bool
+	TOS               uint8
+	DontWaitLastReply bool // Don't wait out Timeout for the last packet's reply.
+}
+
+// ICMPProbeResult represents the outcome of an ICMP probe run.
+type ICMPProbeResult struct {
+	ResolvedSource string
+	ResolvedDest   string
+	RTTs           []time.Duration // One entry per packet; zero value means no reply.
+	PacketsSent    int
+	PacketsRecv    int
+	LossPercent    float64
+	MinRTT         time.Duration
+	AvgRTT         time.Duration
+	MaxRTT         time.Duration
+	StdDevRTT      time.Duration
+	TTL            int
+	UsedRawSocket  bool // False when the unprivileged SOCK_DGRAM path was used.
+}
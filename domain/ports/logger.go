@@ -0,0 +1,20 @@
+package ports
+
+// Logger emits structured, level-tagged log records to the host, modeled on
+// hclog.Logger: kv is a flat list of alternating key/value pairs, and With
+// and Named return a derived Logger rather than mutating the receiver.
+type Logger interface {
+	Trace(msg string, kv ...any)
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+
+	// With returns a Logger that includes kv on every record it emits, in
+	// addition to any its parent already carries.
+	With(kv ...any) Logger
+
+	// Named returns a Logger whose records are tagged with name, qualified
+	// by any name its parent already carries (e.g. "plugin.http").
+	Named(name string) Logger
+}
@@ -0,0 +1,32 @@
+package ports
+
+import "context"
+
+// Tracer starts spans around an outbound operation (a TCP dial, an HTTP
+// request, a KV lookup, a command execution) so a host can stitch plugin
+// activity into its own trace pipeline. Infrastructure adapters depend on
+// this interface rather than a concrete tracing backend, the same way they
+// depend on ExecClient or ICMPClient instead of an OS-specific exec path.
+type Tracer interface {
+	// StartSpan begins a span named name and returns it along with a context
+	// carrying it, mirroring the shape of OpenTelemetry's
+	// Tracer.Start(ctx, name) (context.Context, Span).
+	StartSpan(ctx context.Context, name string) (Span, context.Context)
+}
+
+// Span records attributes about a single traced operation and reports when
+// it completes. Adapters set attributes as they learn them (e.g. once a TCP
+// dial resolves the negotiated TLS version) and call End when the operation
+// finishes.
+type Span interface {
+	// SetAttribute attaches a key/value pair to the span, e.g.
+	// "net.peer.name" / "example.com".
+	SetAttribute(key string, value any)
+
+	// SetError marks the span as failed, recording err. A nil err is a no-op.
+	SetError(err error)
+
+	// End completes the span. Implementations export it (e.g. via a
+	// host_trace_export host function) at this point.
+	End()
+}
@@ -1,15 +1,34 @@
+// Package config decodes and validates plugin configuration maps into the
+// typed structs plugin code actually works with.
 package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/reglet-dev/reglet-sdk/go/wireformat"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
-// Validate maps the config map to the target struct using JSON tags.
-// Note: This implementation currently only performs type mapping via JSON.
-// Field validation (required, etc.) is not yet enforced at this level
-// unless a validator library is integrated.
-func Validate(cfg map[string]any, target any) error {
+// Validate maps cfg onto target using JSON tags, then enforces two layers
+// of constraints: target's own "validate" struct tags (required, url,
+// min=N) and, when schema is non-empty, a JSON Schema compiled from schema
+// and checked against cfg itself. Both layers run in full - Validate does
+// not stop at the first failing field - and every failure is collected
+// into a single *wireformat.ErrorDetail of Type "validation", with one
+// FieldError per failing field addressed by JSON pointer.
+//
+// schema is optional because, unlike the plugin-definition side of this
+// SDK (application/plugin.DefinePlugin calls schema.GenerateSchema to
+// produce one), this package has no wired source of a generated schema:
+// pass the config struct's own schema when the caller has one, or omit it
+// to fall back to struct-tag validation alone.
+func Validate(cfg map[string]any, target any, schema ...json.RawMessage) error {
 	b, err := json.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -17,5 +36,134 @@ func Validate(cfg map[string]any, target any) error {
 	if err := json.Unmarshal(b, target); err != nil {
 		return fmt.Errorf("failed to unmarshal config to struct: %w", err)
 	}
+
+	var fields []wireformat.FieldError
+	if len(schema) > 0 && len(schema[0]) > 0 {
+		fields = append(fields, validateSchema(schema[0], cfg)...)
+	}
+	fields = append(fields, validateTags(target)...)
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &wireformat.ErrorDetail{
+		Message: fmt.Sprintf("config validation failed: %d field(s) invalid", len(fields)),
+		Type:    "validation",
+		Fields:  fields,
+	}
+}
+
+// validateSchema compiles schema and validates cfg against it, flattening
+// every leaf cause of a jsonschema.ValidationError into a FieldError.
+func validateSchema(schema json.RawMessage, cfg map[string]any) []wireformat.FieldError {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("config.json", strings.NewReader(string(schema))); err != nil {
+		return []wireformat.FieldError{{Message: fmt.Sprintf("invalid config schema: %v", err)}}
+	}
+	sch, err := compiler.Compile("config.json")
+	if err != nil {
+		return []wireformat.FieldError{{Message: fmt.Sprintf("invalid config schema: %v", err)}}
+	}
+
+	if err := sch.Validate(cfg); err != nil {
+		var ve *jsonschema.ValidationError
+		if errors.As(err, &ve) {
+			return flattenSchemaErrors(ve)
+		}
+		return []wireformat.FieldError{{Message: err.Error()}}
+	}
 	return nil
 }
+
+// flattenSchemaErrors walks a jsonschema.ValidationError's Causes tree down
+// to its leaves, which are the actual failing fields; the root and any
+// intermediate node just describe which sub-schema rejected the document.
+func flattenSchemaErrors(ve *jsonschema.ValidationError) []wireformat.FieldError {
+	if len(ve.Causes) == 0 {
+		return []wireformat.FieldError{{Pointer: ve.InstanceLocation, Message: ve.Message}}
+	}
+	var fields []wireformat.FieldError
+	for _, cause := range ve.Causes {
+		fields = append(fields, flattenSchemaErrors(cause)...)
+	}
+	return fields
+}
+
+// validateTags runs every exported field's "validate" struct tag rules
+// against a decoded target. Supported rules: required, url, min=N (the
+// field's len() must be at least N; applies to strings, slices, arrays and
+// maps, and is ignored for other kinds).
+func validateTags(target any) []wireformat.FieldError {
+	v := reflect.ValueOf(target)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []wireformat.FieldError
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		fields = append(fields, checkFieldRules(fieldPointer(sf), v.Field(i), tag)...)
+	}
+	return fields
+}
+
+// fieldPointer derives a field's JSON pointer from its json tag, falling
+// back to its Go field name when untagged.
+func fieldPointer(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		name = sf.Name
+	}
+	return "/" + name
+}
+
+func checkFieldRules(pointer string, fv reflect.Value, tag string) []wireformat.FieldError {
+	var fields []wireformat.FieldError
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			if fv.IsZero() {
+				fields = append(fields, wireformat.FieldError{Pointer: pointer, Message: "is required"})
+			}
+		case "url":
+			if s := fv.String(); fv.Kind() == reflect.String && s != "" {
+				if _, err := url.ParseRequestURI(s); err != nil {
+					fields = append(fields, wireformat.FieldError{Pointer: pointer, Message: "must be a valid URL"})
+				}
+			}
+		case "min":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				continue
+			}
+			if length, ok := fieldLength(fv); ok && length < n {
+				fields = append(fields, wireformat.FieldError{
+					Pointer: pointer, Message: fmt.Sprintf("must have a length of at least %d", n),
+				})
+			}
+		}
+	}
+	return fields
+}
+
+// fieldLength returns len(fv) for the kinds "min" applies to.
+func fieldLength(fv reflect.Value) (int, bool) {
+	switch fv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return fv.Len(), true
+	default:
+		return 0, false
+	}
+}
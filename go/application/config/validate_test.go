@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/go/wireformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pluginConfig struct {
+	Endpoint string `json:"endpoint" validate:"required,url"`
+	APIKey   string `json:"api_key" validate:"required,min=8"`
+	Timeout  int    `json:"timeout_ms"`
+}
+
+func TestValidate_MapsFields(t *testing.T) {
+	var cfg pluginConfig
+	err := Validate(map[string]any{
+		"endpoint": "https://example.com", "api_key": "supersecret", "timeout_ms": 500,
+	}, &cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", cfg.Endpoint)
+	assert.Equal(t, 500, cfg.Timeout)
+}
+
+func TestValidate_StructTagFailures(t *testing.T) {
+	var cfg pluginConfig
+	err := Validate(map[string]any{"endpoint": "not-a-url", "api_key": "short"}, &cfg)
+	require.Error(t, err)
+
+	var detail *wireformat.ErrorDetail
+	require.ErrorAs(t, err, &detail)
+	assert.Equal(t, "validation", detail.Type)
+	assert.Len(t, detail.Fields, 2)
+
+	pointers := make([]string, len(detail.Fields))
+	for i, f := range detail.Fields {
+		pointers[i] = f.Pointer
+	}
+	assert.Contains(t, pointers, "/endpoint")
+	assert.Contains(t, pointers, "/api_key")
+}
+
+func TestValidate_SchemaFailure(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["endpoint"],
+		"properties": {"timeout_ms": {"type": "integer", "minimum": 0}}
+	}`)
+
+	var cfg pluginConfig
+	err := Validate(map[string]any{"api_key": "supersecret", "timeout_ms": -1}, &cfg, schema)
+	require.Error(t, err)
+
+	var detail *wireformat.ErrorDetail
+	require.ErrorAs(t, err, &detail)
+	assert.NotEmpty(t, detail.Fields)
+}
+
+func TestValidate_NoSchemaNoTagsIsNoop(t *testing.T) {
+	var cfg struct {
+		Name string `json:"name"`
+	}
+	err := Validate(map[string]any{"name": "anything"}, &cfg)
+	assert.NoError(t, err)
+}
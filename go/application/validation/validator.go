@@ -128,5 +128,10 @@ func (v *CapabilityValidator) Validate(manifest *entities.Manifest) (*entities.V
 		validateSection("kv", grants.KV)
 	}
 
+	// Check ICMP
+	if grants.ICMP != nil && len(grants.ICMP.Rules) > 0 {
+		validateSection("icmp", grants.ICMP)
+	}
+
 	return result, nil
 }
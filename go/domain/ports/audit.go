@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// CapabilityDecision is the outcome of a single capability check.
+type CapabilityDecision string
+
+const (
+	// CapabilityAllowed means the request matched a granted rule (or no
+	// sink-visible rule at all, in which case MatchedRule is empty).
+	CapabilityAllowed CapabilityDecision = "allow"
+	// CapabilityDenied means no granted rule matched the request.
+	CapabilityDenied CapabilityDecision = "deny"
+)
+
+// AuditSink receives one record per capability check a CapabilityChecker
+// performs, allow or deny, so an operator can see which rules matched, how
+// often, and which near-miss patterns show up often enough to suggest a
+// plugin needs a new grant - rather than the checker being an opaque yes/no
+// oracle.
+type AuditSink interface {
+	// RecordDecision is called once per Check* call. kind is the capability
+	// kind ("network", "fs", "env", "exec", "kv"); request is the typed
+	// request value that was checked (e.g. entities.NetworkRequest);
+	// matchedRule is the rule that produced decision, or empty if the
+	// checker doesn't expose which rule matched.
+	RecordDecision(ctx context.Context, pluginName, kind string, request any, decision CapabilityDecision, matchedRule string, latency time.Duration)
+}
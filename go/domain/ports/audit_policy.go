@@ -0,0 +1,243 @@
+package ports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet-sdk/go/domain/entities"
+)
+
+// PolicyAuditRecord is one Check* call an AuditingPolicy recorded: which request was checked against which grants, the decision, the
+// caller's service/operation (see WithAuditCaller), and when it happened.
+type PolicyAuditRecord struct {
+	Timestamp   time.Time
+	Service     string
+	Operation   string
+	Kind        string // "network", "fs", "env", "exec", "kv"
+	Request     any
+	Grants      *entities.GrantSet
+	Decision    CapabilityDecision
+	MatchedRule string // the specific grant rule that decided Decision, "" if none matched
+	DryRun      bool   // true if Decision was Denied but AuditingPolicy forced the call to allow
+}
+
+// PolicyAuditSink receives one PolicyAuditRecord per Check* call an
+// AuditingPolicy makes; Evaluate* calls produce no record. This is a separate interface from AuditSink
+// (used by the hostfuncs.CapabilityChecker layer below Policy) because a
+// PolicyAuditRecord carries the matched GrantSet and caller service/
+// operation, neither of which the Policy interface's Check*/Evaluate*
+// methods have access to.
+type PolicyAuditSink interface {
+	RecordPolicyDecision(record PolicyAuditRecord)
+}
+
+// AuditingPolicy decorates a Policy, recording every Check* call to a
+// PolicyAuditSink before returning the wrapped Policy's decision; Evaluate*
+// calls pass straight through and are not recorded. In
+// DryRun mode, a Check* call that would deny still records a Denied
+// decision but returns true, so operators can tighten grants and diff the
+// audit trail against production traffic before actually enforcing them;
+// Evaluate* methods are never affected by DryRun, since they're already
+// side-effect-free decision lookups.
+type AuditingPolicy struct {
+	policy    Policy
+	sink      PolicyAuditSink
+	service   string
+	operation string
+	dryRun    bool
+}
+
+// AuditingPolicyOption configures an AuditingPolicy.
+type AuditingPolicyOption func(*AuditingPolicy)
+
+// WithAuditCaller tags every record this AuditingPolicy emits with service
+// and operation, identifying who's calling Check*/Evaluate* (e.g. the
+// plugin service name and the operation being observed). Policy's methods
+// don't carry this information themselves, so it's fixed per AuditingPolicy
+// instance - wrap a fresh AuditingPolicy per service/operation pairing
+// that needs distinct attribution.
+func WithAuditCaller(service, operation string) AuditingPolicyOption {
+	return func(a *AuditingPolicy) {
+		a.service = service
+		a.operation = operation
+	}
+}
+
+// WithAuditDryRun puts the AuditingPolicy in dry-run mode; see
+// AuditingPolicy's doc comment.
+func WithAuditDryRun(enabled bool) AuditingPolicyOption {
+	return func(a *AuditingPolicy) {
+		a.dryRun = enabled
+	}
+}
+
+// NewAuditingPolicy wraps policy, recording every Check* call to sink.
+func NewAuditingPolicy(policy Policy, sink PolicyAuditSink, opts ...AuditingPolicyOption) *AuditingPolicy {
+	a := &AuditingPolicy{policy: policy, sink: sink}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+var _ Policy = (*AuditingPolicy)(nil)
+
+// record emits one PolicyAuditRecord and, in dry-run mode, turns a denial
+// into an allow.
+func (a *AuditingPolicy) record(kind string, req any, grants *entities.GrantSet, allowed bool, matchedRule string) (bool, string) {
+	decision := CapabilityAllowed
+	if !allowed {
+		decision = CapabilityDenied
+	}
+	a.sink.RecordPolicyDecision(PolicyAuditRecord{
+		Timestamp:   time.Now(),
+		Service:     a.service,
+		Operation:   a.operation,
+		Kind:        kind,
+		Request:     req,
+		Grants:      grants,
+		Decision:    decision,
+		MatchedRule: matchedRule,
+		DryRun:      a.dryRun && !allowed,
+	})
+	return allowed || a.dryRun, matchedRule
+}
+
+func (a *AuditingPolicy) CheckNetwork(req entities.NetworkRequest, grants *entities.GrantSet) (bool, string) {
+	allowed, rule := a.policy.CheckNetwork(req, grants)
+	return a.record("network", req, grants, allowed, rule)
+}
+
+func (a *AuditingPolicy) CheckFileSystem(req entities.FileSystemRequest, grants *entities.GrantSet) (bool, string) {
+	allowed, rule := a.policy.CheckFileSystem(req, grants)
+	return a.record("fs", req, grants, allowed, rule)
+}
+
+func (a *AuditingPolicy) CheckEnvironment(req entities.EnvironmentRequest, grants *entities.GrantSet) (bool, string) {
+	allowed, rule := a.policy.CheckEnvironment(req, grants)
+	return a.record("env", req, grants, allowed, rule)
+}
+
+func (a *AuditingPolicy) CheckExec(req entities.ExecCapabilityRequest, grants *entities.GrantSet) (bool, string) {
+	allowed, rule := a.policy.CheckExec(req, grants)
+	return a.record("exec", req, grants, allowed, rule)
+}
+
+func (a *AuditingPolicy) CheckKeyValue(req entities.KeyValueRequest, grants *entities.GrantSet) (bool, string) {
+	allowed, rule := a.policy.CheckKeyValue(req, grants)
+	return a.record("kv", req, grants, allowed, rule)
+}
+
+// Evaluate methods pass straight through to the wrapped Policy: they're
+// already side-effect-free by convention (see Policy's doc comment), so
+// they're not recorded or subject to DryRun.
+
+func (a *AuditingPolicy) EvaluateNetwork(req entities.NetworkRequest, grants *entities.GrantSet) (bool, string) {
+	return a.policy.EvaluateNetwork(req, grants)
+}
+
+func (a *AuditingPolicy) EvaluateFileSystem(req entities.FileSystemRequest, grants *entities.GrantSet) (bool, string) {
+	return a.policy.EvaluateFileSystem(req, grants)
+}
+
+func (a *AuditingPolicy) EvaluateEnvironment(req entities.EnvironmentRequest, grants *entities.GrantSet) (bool, string) {
+	return a.policy.EvaluateEnvironment(req, grants)
+}
+
+func (a *AuditingPolicy) EvaluateExec(req entities.ExecCapabilityRequest, grants *entities.GrantSet) (bool, string) {
+	return a.policy.EvaluateExec(req, grants)
+}
+
+func (a *AuditingPolicy) EvaluateKeyValue(req entities.KeyValueRequest, grants *entities.GrantSet) (bool, string) {
+	return a.policy.EvaluateKeyValue(req, grants)
+}
+
+// WriterAuditSink is a PolicyAuditSink that writes one JSON line per record
+// to an io.Writer, so an operator can pipe a plugin's policy audit trail to
+// a file or log aggregator without standing up a metrics pipeline.
+type WriterAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterAuditSink returns a WriterAuditSink writing to w.
+func NewWriterAuditSink(w io.Writer) *WriterAuditSink {
+	return &WriterAuditSink{w: w}
+}
+
+var _ PolicyAuditSink = (*WriterAuditSink)(nil)
+
+// RecordPolicyDecision implements PolicyAuditSink.
+func (s *WriterAuditSink) RecordPolicyDecision(record PolicyAuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(policyAuditRecordJSON{
+		Timestamp: record.Timestamp, Service: record.Service, Operation: record.Operation,
+		Kind: record.Kind, Request: fmt.Sprintf("%+v", record.Request),
+		Decision: record.Decision, MatchedRule: record.MatchedRule, DryRun: record.DryRun,
+	})
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	_, _ = s.w.Write(b)
+}
+
+// policyAuditRecordJSON is PolicyAuditRecord's wire shape for
+// WriterAuditSink: Request is rendered as a string (its concrete type
+// varies by Kind and isn't necessarily JSON-marshalable), and Grants is
+// omitted since it's redundant with the request that was checked against
+// it and can be large.
+type policyAuditRecordJSON struct {
+	Timestamp   time.Time          `json:"timestamp"`
+	Service     string             `json:"service,omitempty"`
+	Operation   string             `json:"operation,omitempty"`
+	Kind        string             `json:"kind"`
+	Request     string             `json:"request"`
+	Decision    CapabilityDecision `json:"decision"`
+	MatchedRule string             `json:"matched_rule,omitempty"`
+	DryRun      bool               `json:"dry_run,omitempty"`
+}
+
+// ChannelAuditSink is a PolicyAuditSink that delivers records on a typed
+// channel, for a host-side event subsystem to consume directly instead of
+// parsing a log stream. Records are sent without blocking Check*/Evaluate*
+// callers: if the channel's buffer is full, the record is dropped rather
+// than stalling plugin execution.
+type ChannelAuditSink struct {
+	records chan PolicyAuditRecord
+}
+
+// NewChannelAuditSink returns a ChannelAuditSink whose channel has the
+// given buffer size. Records returns the channel to range over; it's
+// closed by Close.
+func NewChannelAuditSink(buffer int) *ChannelAuditSink {
+	return &ChannelAuditSink{records: make(chan PolicyAuditRecord, buffer)}
+}
+
+var _ PolicyAuditSink = (*ChannelAuditSink)(nil)
+
+// Records returns the channel records are delivered on.
+func (s *ChannelAuditSink) Records() <-chan PolicyAuditRecord {
+	return s.records
+}
+
+// RecordPolicyDecision implements PolicyAuditSink.
+func (s *ChannelAuditSink) RecordPolicyDecision(record PolicyAuditRecord) {
+	select {
+	case s.records <- record:
+	default:
+		// Buffer full and no consumer draining it fast enough - drop
+		// rather than block the caller mid capability check.
+	}
+}
+
+// Close closes the records channel. Callers must stop calling
+// RecordPolicyDecision before calling Close.
+func (s *ChannelAuditSink) Close() {
+	close(s.records)
+}
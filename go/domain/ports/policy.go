@@ -2,18 +2,22 @@ package ports
 
 import "github.com/reglet-dev/reglet-sdk/go/domain/entities"
 
-// Policy enforces capability grants against runtime requests.
+// Policy enforces capability grants against runtime requests. Every method
+// returns the specific rule that decided the outcome alongside the bool, so
+// a caller like hostfuncs.CapabilityChecker can record which grant matched
+// instead of just allow/deny - matchedRule is "" when nothing in grants
+// matched at all (e.g. an empty GrantSet).
 type Policy interface {
-	CheckNetwork(req entities.NetworkRequest, grants *entities.GrantSet) bool
-	CheckFileSystem(req entities.FileSystemRequest, grants *entities.GrantSet) bool
-	CheckEnvironment(req entities.EnvironmentRequest, grants *entities.GrantSet) bool
-	CheckExec(req entities.ExecCapabilityRequest, grants *entities.GrantSet) bool
-	CheckKeyValue(req entities.KeyValueRequest, grants *entities.GrantSet) bool
+	CheckNetwork(req entities.NetworkRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	CheckFileSystem(req entities.FileSystemRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	CheckEnvironment(req entities.EnvironmentRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	CheckExec(req entities.ExecCapabilityRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	CheckKeyValue(req entities.KeyValueRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
 
 	// Evaluate methods return the decision without side effects (like logging denials).
-	EvaluateNetwork(req entities.NetworkRequest, grants *entities.GrantSet) bool
-	EvaluateFileSystem(req entities.FileSystemRequest, grants *entities.GrantSet) bool
-	EvaluateEnvironment(req entities.EnvironmentRequest, grants *entities.GrantSet) bool
-	EvaluateExec(req entities.ExecCapabilityRequest, grants *entities.GrantSet) bool
-	EvaluateKeyValue(req entities.KeyValueRequest, grants *entities.GrantSet) bool
+	EvaluateNetwork(req entities.NetworkRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	EvaluateFileSystem(req entities.FileSystemRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	EvaluateEnvironment(req entities.EnvironmentRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	EvaluateExec(req entities.ExecCapabilityRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
+	EvaluateKeyValue(req entities.KeyValueRequest, grants *entities.GrantSet) (allowed bool, matchedRule string)
 }
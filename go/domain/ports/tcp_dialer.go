@@ -16,6 +16,60 @@ type TCPDialer interface {
 
 	// DialSecure establishes a TCP connection with timeout and optional TLS.
 	DialSecure(ctx context.Context, address string, timeoutMs int, tls bool) (TCPConnection, error)
+
+	// DialPreferred resolves address's host to both its A and AAAA records
+	// and dials the family named by opts.PreferredIPProtocol first. If that
+	// family has no addresses, or every address in it fails to connect, and
+	// opts.IPProtocolFallback is true, it retries against the other family.
+	// Implementations should distinguish "preferred family has no addresses
+	// at all" (a DNS-shaped failure) from "addresses exist but every dial
+	// was refused/timed out" (a TCP-shaped failure) in the returned error,
+	// so a caller can errors.As into the error type that matches what
+	// actually went wrong.
+	DialPreferred(ctx context.Context, address string, opts DialOptions) (TCPConnection, error)
+}
+
+// DialOptions configures DialPreferred's address-family selection and bind
+// behavior.
+type DialOptions struct {
+	// PreferredIPProtocol is "ip4", "ip6", or "auto" (use whatever order the
+	// resolver returns, i.e. today's Dial/DialWithTimeout/DialSecure
+	// behavior). Empty is treated as "auto".
+	PreferredIPProtocol string
+
+	// IPProtocolFallback allows retrying against the other address family
+	// when PreferredIPProtocol's family is unavailable or unreachable. If
+	// false, DialPreferred fails as soon as the preferred family is
+	// exhausted rather than trying the other one.
+	IPProtocolFallback bool
+
+	// SourceIPCIDR, if set, restricts the local address DialPreferred binds
+	// from to one within this CIDR (e.g. to dial out from a specific
+	// interface or address pool). Empty means no restriction.
+	SourceIPCIDR string
+}
+
+// PreferredFamilyOrder returns the address families DialPreferred should
+// try, in order. "auto" (or an empty PreferredIPProtocol) returns a single
+// empty string, meaning "no family preference - use whatever order the
+// resolver already returns." Otherwise it returns the preferred family
+// alone, or the preferred family followed by the other one when
+// opts.IPProtocolFallback is true.
+func PreferredFamilyOrder(opts DialOptions) []string {
+	switch opts.PreferredIPProtocol {
+	case "ip4":
+		if opts.IPProtocolFallback {
+			return []string{"ip4", "ip6"}
+		}
+		return []string{"ip4"}
+	case "ip6":
+		if opts.IPProtocolFallback {
+			return []string{"ip6", "ip4"}
+		}
+		return []string{"ip6"}
+	default:
+		return []string{""}
+	}
 }
 
 // TCPConnection represents an established TCP connection.
@@ -52,4 +106,10 @@ type TCPConnection interface {
 
 	// TLSCertNotAfter returns the expiration time of the peer certificate.
 	TLSCertNotAfter() *time.Time
+
+	// AddressFamily returns which address family the connection actually
+	// used: "ip4" or "ip6". Connections established via Dial,
+	// DialWithTimeout, or DialSecure (no family preference) still report
+	// whichever family the resolver and dialer happened to pick.
+	AddressFamily() string
 }
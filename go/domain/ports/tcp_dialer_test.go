@@ -0,0 +1,28 @@
+package ports_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet-sdk/go/domain/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferredFamilyOrder(t *testing.T) {
+	cases := []struct {
+		name string
+		opts ports.DialOptions
+		want []string
+	}{
+		{"ip4 no fallback", ports.DialOptions{PreferredIPProtocol: "ip4"}, []string{"ip4"}},
+		{"ip4 with fallback", ports.DialOptions{PreferredIPProtocol: "ip4", IPProtocolFallback: true}, []string{"ip4", "ip6"}},
+		{"ip6 no fallback", ports.DialOptions{PreferredIPProtocol: "ip6"}, []string{"ip6"}},
+		{"ip6 with fallback", ports.DialOptions{PreferredIPProtocol: "ip6", IPProtocolFallback: true}, []string{"ip6", "ip4"}},
+		{"auto", ports.DialOptions{PreferredIPProtocol: "auto"}, []string{""}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ports.PreferredFamilyOrder(tc.opts))
+		})
+	}
+}
@@ -5,25 +5,26 @@
 package sdk
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
-// NetworkError represents a network operation failure.
-type NetworkError struct {
+// NetworkOpError represents a network operation failure.
+type NetworkOpError struct {
 	Operation string // "dns_lookup", "http_request", "tcp_connect", etc.
 	Target    string // Hostname, URL, or address
 	Err       error  // Underlying error
 }
 
-func (e *NetworkError) Error() string {
+func (e *NetworkOpError) Error() string {
 	if e.Target != "" {
 		return fmt.Sprintf("network %s failed for %s: %v", e.Operation, e.Target, e.Err)
 	}
 	return fmt.Sprintf("network %s failed: %v", e.Operation, e.Err)
 }
 
-func (e *NetworkError) Unwrap() error {
+func (e *NetworkOpError) Unwrap() error {
 	return e.Err
 }
 
@@ -58,20 +59,20 @@ func (e *CapabilityError) Error() string {
 	return fmt.Sprintf("missing capability: %s", e.Required)
 }
 
-// ConfigError represents a configuration validation error.
-type ConfigError struct {
+// ConfigValidationError represents a configuration validation error.
+type ConfigValidationError struct {
 	Field string // Field name that failed validation
 	Err   error  // Underlying validation error
 }
 
-func (e *ConfigError) Error() string {
+func (e *ConfigValidationError) Error() string {
 	if e.Field != "" {
 		return fmt.Sprintf("config validation failed for field '%s': %v", e.Field, e.Err)
 	}
 	return fmt.Sprintf("config validation failed: %v", e.Err)
 }
 
-func (e *ConfigError) Unwrap() error {
+func (e *ConfigValidationError) Unwrap() error {
 	return e.Err
 }
 
@@ -220,3 +221,33 @@ func (e *WireFormatError) Error() string {
 func (e *WireFormatError) Unwrap() error {
 	return e.Err
 }
+
+// Sentinel errors a handler can wrap directly (e.g.
+// fmt.Errorf("missing host: %w", sdk.ErrConfig)) to get ToErrorDetail
+// classification without constructing one of the structs above. Check
+// errors.Is(err, sdk.ErrConfig) to test for one of these.
+var (
+	ErrConfig           = errors.New("config error")
+	ErrNetwork          = errors.New("network error")
+	ErrCapabilityDenied = errors.New("capability denied")
+	ErrTimeout          = errors.New("timeout")
+	ErrValidation       = errors.New("validation error")
+	ErrExec             = errors.New("exec error")
+	ErrKeyValue         = errors.New("key-value error")
+)
+
+// sentinelErrorTypes maps each sentinel above to the ErrorDetail.Type (and,
+// where one applies, Code) ToErrorDetail assigns when errors.Is matches it.
+var sentinelErrorTypes = []struct {
+	sentinel error
+	errType  string
+	code     string
+}{
+	{ErrConfig, "config", ""},
+	{ErrNetwork, "network", ""},
+	{ErrCapabilityDenied, "capability", ""},
+	{ErrTimeout, "timeout", ""},
+	{ErrValidation, "validation", ""},
+	{ErrExec, "exec", ""},
+	{ErrKeyValue, "capability", "keyvalue"},
+}
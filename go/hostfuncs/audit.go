@@ -0,0 +1,160 @@
+package hostfuncs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet-sdk/go/domain/ports"
+)
+
+// SlogAuditSink is a ports.AuditSink that emits one structured log record
+// per capability check via the given *slog.Logger, at Info for allows and
+// Warn for denies so a deny stands out in a host's log stream without
+// needing a separate audit pipeline.
+type SlogAuditSink struct {
+	logger *slog.Logger
+}
+
+// NewSlogAuditSink returns a SlogAuditSink that logs through logger, or
+// slog.Default() if logger is nil.
+func NewSlogAuditSink(logger *slog.Logger) *SlogAuditSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogAuditSink{logger: logger}
+}
+
+var _ ports.AuditSink = (*SlogAuditSink)(nil)
+
+// RecordDecision implements ports.AuditSink.
+func (s *SlogAuditSink) RecordDecision(ctx context.Context, pluginName, kind string, request any, decision ports.CapabilityDecision, matchedRule string, latency time.Duration) {
+	level := slog.LevelInfo
+	if decision == ports.CapabilityDenied {
+		level = slog.LevelWarn
+	}
+	s.logger.Log(ctx, level, "capability check",
+		"plugin", pluginName,
+		"kind", kind,
+		"decision", string(decision),
+		"matched_rule", matchedRule,
+		"latency_ms", latency.Milliseconds(),
+		"request", fmt.Sprintf("%+v", request),
+	)
+}
+
+// MetricsAuditSink is a ports.AuditSink that accumulates Prometheus-style
+// counters and a check-latency histogram in memory, with no dependency on
+// an actual Prometheus client library. Gather renders the current values in
+// Prometheus text exposition format, ready to be served from a host's own
+// /metrics endpoint.
+type MetricsAuditSink struct {
+	mu         sync.Mutex
+	checkCount map[metricsCheckKey]int64
+	latencyObs map[string]*latencyHistogram // keyed on kind
+}
+
+// metricsCheckKey identifies one reglet_capability_checks_total series.
+type metricsCheckKey struct {
+	plugin   string
+	kind     string
+	decision ports.CapabilityDecision
+}
+
+// latencyBucketsSeconds are the bucket upper bounds for
+// reglet_capability_check_seconds, chosen to resolve a check that should be
+// a few microseconds of in-memory pattern matching from one that's
+// unexpectedly slow (e.g. blocked on a syscall it shouldn't need).
+var latencyBucketsSeconds = []float64{0.00001, 0.0001, 0.001, 0.01, 0.1, 1}
+
+// latencyHistogram accumulates per-bucket counts and a running sum, the
+// same shape a Prometheus client-side histogram tracks internally.
+type latencyHistogram struct {
+	bucketCounts []int64 // parallel to latencyBucketsSeconds, cumulative is computed at render time
+	sum          float64
+	count        int64
+}
+
+// NewMetricsAuditSink returns an empty MetricsAuditSink ready to record
+// decisions and be scraped via Gather.
+func NewMetricsAuditSink() *MetricsAuditSink {
+	return &MetricsAuditSink{
+		checkCount: make(map[metricsCheckKey]int64),
+		latencyObs: make(map[string]*latencyHistogram),
+	}
+}
+
+var _ ports.AuditSink = (*MetricsAuditSink)(nil)
+
+// RecordDecision implements ports.AuditSink.
+func (m *MetricsAuditSink) RecordDecision(ctx context.Context, pluginName, kind string, request any, decision ports.CapabilityDecision, matchedRule string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.checkCount[metricsCheckKey{plugin: pluginName, kind: kind, decision: decision}]++
+
+	hist, ok := m.latencyObs[kind]
+	if !ok {
+		hist = &latencyHistogram{bucketCounts: make([]int64, len(latencyBucketsSeconds))}
+		m.latencyObs[kind] = hist
+	}
+	seconds := latency.Seconds()
+	hist.sum += seconds
+	hist.count++
+	for i, upper := range latencyBucketsSeconds {
+		if seconds <= upper {
+			hist.bucketCounts[i]++
+			break
+		}
+	}
+}
+
+// Gather renders the accumulated counters and histogram in Prometheus text
+// exposition format.
+func (m *MetricsAuditSink) Gather() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP reglet_capability_checks_total Total capability checks performed.\n")
+	b.WriteString("# TYPE reglet_capability_checks_total counter\n")
+	for key, count := range m.checkCount {
+		fmt.Fprintf(&b, "reglet_capability_checks_total{plugin=%q,kind=%q,decision=%q} %d\n",
+			key.plugin, key.kind, string(key.decision), count)
+	}
+
+	b.WriteString("# HELP reglet_capability_check_seconds Time spent evaluating a capability check.\n")
+	b.WriteString("# TYPE reglet_capability_check_seconds histogram\n")
+	for kind, hist := range m.latencyObs {
+		var cumulative int64
+		for i, upper := range latencyBucketsSeconds {
+			cumulative += hist.bucketCounts[i]
+			fmt.Fprintf(&b, "reglet_capability_check_seconds_bucket{kind=%q,le=%q} %d\n", kind, formatBucketBound(upper), cumulative)
+		}
+		fmt.Fprintf(&b, "reglet_capability_check_seconds_bucket{kind=%q,le=\"+Inf\"} %d\n", kind, hist.count)
+		fmt.Fprintf(&b, "reglet_capability_check_seconds_sum{kind=%q} %v\n", kind, hist.sum)
+		fmt.Fprintf(&b, "reglet_capability_check_seconds_count{kind=%q} %d\n", kind, hist.count)
+	}
+
+	return b.String()
+}
+
+// formatBucketBound renders a bucket upper bound for the "le" label as a
+// plain decimal with trailing zeros removed (0.00001, 0.1, 1).
+func formatBucketBound(upper float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", upper), "0"), ".")
+}
+
+// noopAuditSink is the default ports.AuditSink a CapabilityChecker uses
+// when WithCapabilityAuditSink isn't passed, so Check* methods don't need a
+// nil check before recording every decision.
+type noopAuditSink struct{}
+
+func (noopAuditSink) RecordDecision(context.Context, string, string, any, ports.CapabilityDecision, string, time.Duration) {
+}
+
+var _ ports.AuditSink = noopAuditSink{}
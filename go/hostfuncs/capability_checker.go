@@ -5,18 +5,37 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/reglet-dev/reglet-sdk/go/domain/entities"
 	"github.com/reglet-dev/reglet-sdk/go/domain/policy"
 	"github.com/reglet-dev/reglet-sdk/go/domain/ports"
+	"github.com/reglet-dev/reglet-sdk/go/infrastructure/wazero/capability"
 )
 
+// CapabilityDeniedError is returned when a Check* call is denied by the
+// glob/regex Matcher layer rather than (or in addition to) the typed
+// GrantSet policy. Pattern is the specific allow/deny rule that decided
+// the outcome.
+type CapabilityDeniedError struct {
+	PluginName string
+	Kind       string
+	Pattern    string
+}
+
+func (e *CapabilityDeniedError) Error() string {
+	return fmt.Sprintf("capability denied: plugin %q matched %s pattern %q", e.PluginName, e.Kind, e.Pattern)
+}
+
 // CapabilityChecker checks if operations are allowed based on granted capabilities.
 // It uses the SDK's typed Policy for capability enforcement.
 type CapabilityChecker struct {
 	policy              ports.Policy
 	grantedCapabilities map[string]*entities.GrantSet
 	cwd                 string // Current working directory for resolving relative paths
+	auditSink           ports.AuditSink
+	dryRun              bool
+	matcher             *capability.Matcher
 }
 
 // CapabilityCheckerOption configures a CapabilityChecker.
@@ -25,6 +44,21 @@ type CapabilityCheckerOption func(*capabilityCheckerConfig)
 type capabilityCheckerConfig struct {
 	cwd               string
 	symlinkResolution bool
+	auditSink         ports.AuditSink
+	dryRun            bool
+	matcher           *capability.Matcher
+}
+
+// WithCapabilityMatcher adds a glob/regex pattern layer on top of the typed
+// GrantSet policy. A Check* call is denied if m has a deny pattern matching
+// the request, even when the typed policy would have allowed it; it's
+// allowed if the typed policy allows it and m has no deny match (an allow
+// match in m doesn't override a typed-policy denial - m narrows what the
+// typed grants otherwise permit, it doesn't widen it).
+func WithCapabilityMatcher(m *capability.Matcher) CapabilityCheckerOption {
+	return func(c *capabilityCheckerConfig) {
+		c.matcher = m
+	}
 }
 
 // WithCapabilityWorkingDirectory sets the working directory for path resolution.
@@ -41,6 +75,25 @@ func WithCapabilitySymlinkResolution(enabled bool) CapabilityCheckerOption {
 	}
 }
 
+// WithCapabilityAuditSink makes the checker emit one record per Check* call,
+// allow or deny, to sink. Without this option the checker records to a
+// no-op sink.
+func WithCapabilityAuditSink(sink ports.AuditSink) CapabilityCheckerOption {
+	return func(c *capabilityCheckerConfig) {
+		c.auditSink = sink
+	}
+}
+
+// WithCapabilityDryRun puts the checker in dry-run mode: a check that would
+// otherwise deny still records a CapabilityDenied decision to the audit
+// sink, but Check* returns nil instead of an error. Useful when onboarding
+// a plugin to see what it would be denied without actually blocking it.
+func WithCapabilityDryRun(enabled bool) CapabilityCheckerOption {
+	return func(c *capabilityCheckerConfig) {
+		c.dryRun = enabled
+	}
+}
+
 // NewCapabilityChecker creates a new capability checker with the given capabilities.
 // The cwd is obtained at construction time to avoid side-effects during capability checks.
 func NewCapabilityChecker(caps map[string]*entities.GrantSet, opts ...CapabilityCheckerOption) *CapabilityChecker {
@@ -57,6 +110,11 @@ func NewCapabilityChecker(caps map[string]*entities.GrantSet, opts ...Capability
 		cfg.cwd, _ = os.Getwd() // Best effort - empty string will cause relative paths to fail safely
 	}
 
+	auditSink := cfg.auditSink
+	if auditSink == nil {
+		auditSink = noopAuditSink{}
+	}
+
 	return &CapabilityChecker{
 		policy: policy.NewPolicy(
 			policy.WithWorkingDirectory(cfg.cwd),
@@ -64,85 +122,139 @@ func NewCapabilityChecker(caps map[string]*entities.GrantSet, opts ...Capability
 		),
 		grantedCapabilities: caps,
 		cwd:                 cfg.cwd,
+		auditSink:           auditSink,
+		dryRun:              cfg.dryRun,
+		matcher:             cfg.matcher,
 	}
 }
 
+// record emits one audit record for a Check* call and applies dry-run mode:
+// a denial becomes nil if c.dryRun is set, after still being recorded as
+// CapabilityDenied. kind is the capability kind ("network", "fs", "env",
+// "exec"); req is the typed request that was checked; matchedRule is the
+// specific grant or matcher pattern that decided the outcome, "" if nothing
+// matched at all.
+//
+// RecordDecision takes a context.Context so a future caller can thread
+// request-scoped context through; Check* doesn't accept one today (that
+// would mean changing its signature here and in the mirrored
+// infrastructure/wazero.CapabilityChecker interface, which is out of scope
+// for this change), so context.Background() is passed for now.
+func (c *CapabilityChecker) record(pluginName, kind string, req any, allowed bool, matchedRule string, start time.Time, denyErr error) error {
+	decision := ports.CapabilityAllowed
+	if !allowed {
+		decision = ports.CapabilityDenied
+	}
+	c.auditSink.RecordDecision(context.Background(), pluginName, kind, req, decision, matchedRule, time.Since(start))
+
+	if allowed || c.dryRun {
+		return nil
+	}
+	return denyErr
+}
+
+// matcherDenies consults c.matcher (if any) for kind:subject and reports
+// whether its deny list matched, along with the specific pattern, so a
+// Check* call can be denied even when the typed policy would have allowed
+// it. A matcher allow match never overrides a typed-policy denial - see
+// WithCapabilityMatcher.
+func (c *CapabilityChecker) matcherDenies(kind, subject string) (denied bool, pattern string) {
+	if c.matcher == nil {
+		return false, ""
+	}
+	allowed, matched, p := c.matcher.Decide(kind, subject)
+	if matched && !allowed {
+		return true, p
+	}
+	return false, ""
+}
+
 // CheckNetwork performs typed network capability check.
 func (c *CapabilityChecker) CheckNetwork(pluginName string, req entities.NetworkRequest) error {
+	start := time.Now()
 	grants, ok := c.grantedCapabilities[pluginName]
 	if !ok || grants == nil {
-		return fmt.Errorf("no capabilities granted to plugin %s", pluginName)
+		return c.record(pluginName, "network", req, false, "", start, fmt.Errorf("no capabilities granted to plugin %s", pluginName))
 	}
 
-	if c.policy.CheckNetwork(req, grants) {
-		return nil
+	allowed, rule := c.policy.CheckNetwork(req, grants)
+	subject := fmt.Sprintf("%s:%d", req.Host, req.Port)
+	if denied, pattern := c.matcherDenies("network", subject); denied {
+		return c.record(pluginName, "network", req, false, pattern, start, &CapabilityDeniedError{PluginName: pluginName, Kind: "network", Pattern: pattern})
 	}
-
-	return fmt.Errorf("network capability denied: %s:%d", req.Host, req.Port)
+	return c.record(pluginName, "network", req, allowed, rule, start, fmt.Errorf("network capability denied: %s:%d", req.Host, req.Port))
 }
 
 // CheckNetworkConnection checks if a specific network connection (host:port) is allowed.
 // It uses EvaluateNetwork (silent) first to avoid logspam, and only checks loudly if denied.
 func (c *CapabilityChecker) CheckNetworkConnection(pluginName, host string, port int) error {
+	start := time.Now()
 	grants, ok := c.grantedCapabilities[pluginName]
 	if !ok || grants == nil {
-		return fmt.Errorf("no capabilities granted to plugin %s", pluginName)
+		return c.record(pluginName, "network", entities.NetworkRequest{Host: host, Port: port}, false, "", start, fmt.Errorf("no capabilities granted to plugin %s", pluginName))
 	}
 
 	req := entities.NetworkRequest{Host: host, Port: port}
 
+	if denied, pattern := c.matcherDenies("network", fmt.Sprintf("%s:%d", host, port)); denied {
+		return c.record(pluginName, "network", req, false, pattern, start, &CapabilityDeniedError{PluginName: pluginName, Kind: "network", Pattern: pattern})
+	}
+
 	// 1. Silent Check: See if ANY rule matches this specific request.
-	if c.policy.EvaluateNetwork(req, grants) {
-		return nil
+	if allowed, rule := c.policy.EvaluateNetwork(req, grants); allowed {
+		return c.record(pluginName, "network", req, true, rule, start, nil)
 	}
 
 	// 2. Loud Check: If denied, call CheckNetwork to trigger the DenialHandler (logging).
 	// We know it will return false, but we call it for the side effect.
 	c.policy.CheckNetwork(req, grants)
 
-	return fmt.Errorf("network capability denied: %s:%d", host, port)
+	return c.record(pluginName, "network", req, false, "", start, fmt.Errorf("network capability denied: %s:%d", host, port))
 }
 
 // CheckFileSystem performs typed filesystem capability check.
 func (c *CapabilityChecker) CheckFileSystem(pluginName string, req entities.FileSystemRequest) error {
+	start := time.Now()
 	grants, ok := c.grantedCapabilities[pluginName]
 	if !ok || grants == nil {
-		return fmt.Errorf("no capabilities granted to plugin %s", pluginName)
+		return c.record(pluginName, "fs", req, false, "", start, fmt.Errorf("no capabilities granted to plugin %s", pluginName))
 	}
 
-	if c.policy.CheckFileSystem(req, grants) {
-		return nil
+	allowed, rule := c.policy.CheckFileSystem(req, grants)
+	if denied, pattern := c.matcherDenies("fs", req.Path); denied {
+		return c.record(pluginName, "fs", req, false, pattern, start, &CapabilityDeniedError{PluginName: pluginName, Kind: "fs", Pattern: pattern})
 	}
-
-	return fmt.Errorf("filesystem capability denied: %s %s", req.Operation, req.Path)
+	return c.record(pluginName, "fs", req, allowed, rule, start, fmt.Errorf("filesystem capability denied: %s %s", req.Operation, req.Path))
 }
 
 // CheckEnvironment performs typed environment capability check.
 func (c *CapabilityChecker) CheckEnvironment(pluginName string, req entities.EnvironmentRequest) error {
+	start := time.Now()
 	grants, ok := c.grantedCapabilities[pluginName]
 	if !ok || grants == nil {
-		return fmt.Errorf("no capabilities granted to plugin %s", pluginName)
+		return c.record(pluginName, "env", req, false, "", start, fmt.Errorf("no capabilities granted to plugin %s", pluginName))
 	}
 
-	if c.policy.CheckEnvironment(req, grants) {
-		return nil
+	allowed, rule := c.policy.CheckEnvironment(req, grants)
+	if denied, pattern := c.matcherDenies("env", req.Variable); denied {
+		return c.record(pluginName, "env", req, false, pattern, start, &CapabilityDeniedError{PluginName: pluginName, Kind: "env", Pattern: pattern})
 	}
-
-	return fmt.Errorf("environment capability denied: %s", req.Variable)
+	return c.record(pluginName, "env", req, allowed, rule, start, fmt.Errorf("environment capability denied: %s", req.Variable))
 }
 
 // CheckExec performs typed exec capability check.
 func (c *CapabilityChecker) CheckExec(pluginName string, req entities.ExecCapabilityRequest) error {
+	start := time.Now()
 	grants, ok := c.grantedCapabilities[pluginName]
 	if !ok || grants == nil {
-		return fmt.Errorf("no capabilities granted to plugin %s", pluginName)
+		return c.record(pluginName, "exec", req, false, "", start, fmt.Errorf("no capabilities granted to plugin %s", pluginName))
 	}
 
-	if c.policy.CheckExec(req, grants) {
-		return nil
+	allowed, rule := c.policy.CheckExec(req, grants)
+	if denied, pattern := c.matcherDenies("exec", req.Command); denied {
+		return c.record(pluginName, "exec", req, false, pattern, start, &CapabilityDeniedError{PluginName: pluginName, Kind: "exec", Pattern: pattern})
 	}
-
-	return fmt.Errorf("exec capability denied: %s", req.Command)
+	return c.record(pluginName, "exec", req, allowed, rule, start, fmt.Errorf("exec capability denied: %s", req.Command))
 }
 
 // AllowsPrivateNetwork checks if the plugin is allowed to access private network addresses.
@@ -154,7 +266,8 @@ func (c *CapabilityChecker) AllowsPrivateNetwork(pluginName string) bool {
 
 	// Create a dummy request for private access.
 	req := entities.NetworkRequest{Host: "127.0.0.1", Port: 0}
-	return c.policy.EvaluateNetwork(req, grants)
+	allowed, _ := c.policy.EvaluateNetwork(req, grants)
+	return allowed
 }
 
 // ToCapabilityGetter returns a CapabilityGetter function that uses this checker.
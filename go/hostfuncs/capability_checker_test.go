@@ -0,0 +1,148 @@
+package hostfuncs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet-sdk/go/domain/entities"
+	"github.com/reglet-dev/reglet-sdk/go/domain/ports"
+	"github.com/reglet-dev/reglet-sdk/go/infrastructure/wazero/capability"
+)
+
+// fakePolicy is a ports.Policy whose Check* methods return whatever
+// allowed/matchedRule pair the test configures, so capability_checker_test
+// can exercise CapabilityChecker without the real typed policy.
+type fakePolicy struct {
+	allowed     bool
+	matchedRule string
+}
+
+func (p fakePolicy) CheckNetwork(entities.NetworkRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) CheckFileSystem(entities.FileSystemRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) CheckEnvironment(entities.EnvironmentRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) CheckExec(entities.ExecCapabilityRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) CheckKeyValue(entities.KeyValueRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) EvaluateNetwork(entities.NetworkRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) EvaluateFileSystem(entities.FileSystemRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) EvaluateEnvironment(entities.EnvironmentRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) EvaluateExec(entities.ExecCapabilityRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+func (p fakePolicy) EvaluateKeyValue(entities.KeyValueRequest, *entities.GrantSet) (bool, string) {
+	return p.allowed, p.matchedRule
+}
+
+var _ ports.Policy = fakePolicy{}
+
+// recordingAuditSink captures the last RecordDecision call, so a test can
+// assert what matchedRule value reached the sink.
+type recordingAuditSink struct {
+	decision    ports.CapabilityDecision
+	matchedRule string
+}
+
+func (s *recordingAuditSink) RecordDecision(_ context.Context, _, _ string, _ any, decision ports.CapabilityDecision, matchedRule string, _ time.Duration) {
+	s.decision = decision
+	s.matchedRule = matchedRule
+}
+
+func TestCapabilityChecker_CheckNetwork_RecordsMatchedRuleOnAllow(t *testing.T) {
+	sink := &recordingAuditSink{}
+	c := &CapabilityChecker{
+		policy:              fakePolicy{allowed: true, matchedRule: "NET001-example.com"},
+		grantedCapabilities: map[string]*entities.GrantSet{"plugin-a": {}},
+		auditSink:           sink,
+	}
+
+	if err := c.CheckNetwork("plugin-a", entities.NetworkRequest{Host: "example.com", Port: 443}); err != nil {
+		t.Fatalf("CheckNetwork: %v", err)
+	}
+	if sink.decision != ports.CapabilityAllowed {
+		t.Errorf("decision = %q; want %q", sink.decision, ports.CapabilityAllowed)
+	}
+	if sink.matchedRule != "NET001-example.com" {
+		t.Errorf("matchedRule = %q; want %q", sink.matchedRule, "NET001-example.com")
+	}
+}
+
+func TestCapabilityChecker_CheckNetwork_RecordsPolicyDenialRule(t *testing.T) {
+	sink := &recordingAuditSink{}
+	c := &CapabilityChecker{
+		policy:              fakePolicy{allowed: false, matchedRule: "NET001-denied-host"},
+		grantedCapabilities: map[string]*entities.GrantSet{"plugin-a": {}},
+		auditSink:           sink,
+	}
+
+	if err := c.CheckNetwork("plugin-a", entities.NetworkRequest{Host: "evil.example", Port: 443}); err == nil {
+		t.Fatal("CheckNetwork = nil error; want a denial")
+	}
+	if sink.decision != ports.CapabilityDenied {
+		t.Errorf("decision = %q; want %q", sink.decision, ports.CapabilityDenied)
+	}
+	if sink.matchedRule != "NET001-denied-host" {
+		t.Errorf("matchedRule = %q; want %q", sink.matchedRule, "NET001-denied-host")
+	}
+}
+
+func TestCapabilityChecker_CheckFileSystem_RecordsMatcherPatternOnDeny(t *testing.T) {
+	matcher, err := capability.CompileDocument(capability.PolicyDocument{
+		FS: capability.RuleSet{Deny: []string{"/etc/*"}},
+	})
+	if err != nil {
+		t.Fatalf("CompileDocument: %v", err)
+	}
+
+	sink := &recordingAuditSink{}
+	c := &CapabilityChecker{
+		// The typed policy alone would allow this request; the matcher's
+		// deny pattern must still win, and its pattern - not the typed
+		// policy's rule - is what gets recorded.
+		policy:              fakePolicy{allowed: true, matchedRule: "FS001-read"},
+		grantedCapabilities: map[string]*entities.GrantSet{"plugin-a": {}},
+		auditSink:           sink,
+		matcher:             matcher,
+	}
+
+	if err := c.CheckFileSystem("plugin-a", entities.FileSystemRequest{Path: "/etc/passwd", Operation: "read"}); err == nil {
+		t.Fatal("CheckFileSystem = nil error; want a denial from the matcher")
+	}
+	if sink.decision != ports.CapabilityDenied {
+		t.Errorf("decision = %q; want %q", sink.decision, ports.CapabilityDenied)
+	}
+	if sink.matchedRule != "/etc/*" {
+		t.Errorf("matchedRule = %q; want the matcher's deny pattern %q", sink.matchedRule, "/etc/*")
+	}
+}
+
+func TestCapabilityChecker_CheckExec_NoGrantsRecordsEmptyMatchedRule(t *testing.T) {
+	sink := &recordingAuditSink{}
+	c := &CapabilityChecker{
+		policy:              fakePolicy{allowed: true, matchedRule: "should-not-be-reached"},
+		grantedCapabilities: map[string]*entities.GrantSet{},
+		auditSink:           sink,
+	}
+
+	if err := c.CheckExec("unknown-plugin", entities.ExecCapabilityRequest{Command: "/bin/ls"}); err == nil {
+		t.Fatal("CheckExec = nil error; want a denial for an ungranted plugin")
+	}
+	if sink.matchedRule != "" {
+		t.Errorf("matchedRule = %q; want empty, no grant was ever consulted", sink.matchedRule)
+	}
+}
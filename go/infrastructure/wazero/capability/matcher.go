@@ -0,0 +1,181 @@
+// Package capability compiles a capability policy document - per-kind
+// allow and deny lists of glob or regex patterns - into a Matcher that
+// CapabilityChecker can consult alongside its typed GrantSet checks.
+//
+// A Matcher has no dependency on domain/entities or domain/ports: it
+// matches plain strings ("host:port", a filesystem path, an env var name,
+// a command), so it can sit beside the typed policy layer instead of
+// inside it.
+package capability
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Pattern is a single allow/deny rule. A pattern prefixed with "re:" is
+// compiled as a regular expression against the full subject string;
+// anything else is matched as a filepath.Match-style glob ("*.example.com:443",
+// "/usr/bin/curl", "/etc/*").
+type Pattern struct {
+	raw string
+	re  *regexp.Regexp // nil for glob patterns
+}
+
+// Compile parses raw into a Pattern, compiling it as a regex when prefixed
+// with "re:" and leaving it as a glob otherwise.
+func Compile(raw string) (Pattern, error) {
+	if rest, ok := strings.CutPrefix(raw, "re:"); ok {
+		re, err := regexp.Compile(rest)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("capability: invalid regex pattern %q: %w", raw, err)
+		}
+		return Pattern{raw: raw, re: re}, nil
+	}
+	// filepath.Match rejects a malformed glob (e.g. an unterminated
+	// bracket expression) up front rather than at match time.
+	if _, err := filepath.Match(raw, ""); err != nil {
+		return Pattern{}, fmt.Errorf("capability: invalid glob pattern %q: %w", raw, err)
+	}
+	return Pattern{raw: raw}, nil
+}
+
+// Match reports whether subject satisfies p.
+func (p Pattern) Match(subject string) bool {
+	if p.re != nil {
+		return p.re.MatchString(subject)
+	}
+	ok, _ := filepath.Match(p.raw, subject)
+	return ok
+}
+
+// String returns the pattern as written in the policy document.
+func (p Pattern) String() string { return p.raw }
+
+// kindRules holds one capability kind's allow and deny pattern lists.
+type kindRules struct {
+	allow []Pattern
+	deny  []Pattern
+}
+
+// Matcher evaluates subjects against per-kind allow/deny pattern lists,
+// with deny always taking precedence over allow.
+type Matcher struct {
+	rules map[string]kindRules
+}
+
+// PolicyDocument is the JSON shape a Matcher compiles from:
+//
+//	{
+//	  "network": {"allow": ["*.example.com:443"], "deny": ["re:^169\\.254\\."]},
+//	  "fs":      {"allow": ["/usr/bin/curl"], "deny": ["/etc/*"]},
+//	  "env":     {"allow": ["PATH", "HOME"]},
+//	  "exec":    {"allow": ["/usr/bin/curl", "re:^/opt/.*"]}
+//	}
+//
+// Kinds match CapabilityChecker's: "network", "fs", "env", "exec".
+// TOML isn't supported yet - this module has no vendored TOML decoder to
+// parse it with - so policy documents are JSON-only for now.
+type PolicyDocument struct {
+	Network RuleSet `json:"network"`
+	FS      RuleSet `json:"fs"`
+	Env     RuleSet `json:"env"`
+	Exec    RuleSet `json:"exec"`
+}
+
+// RuleSet is one capability kind's allow and deny pattern lists.
+type RuleSet struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// CompileJSON parses a JSON-encoded PolicyDocument and compiles it into a
+// Matcher.
+func CompileJSON(data []byte) (*Matcher, error) {
+	var doc PolicyDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("capability: parse policy document: %w", err)
+	}
+	return CompileDocument(doc)
+}
+
+// CompileDocument compiles an already-parsed PolicyDocument into a Matcher.
+func CompileDocument(doc PolicyDocument) (*Matcher, error) {
+	m := &Matcher{rules: make(map[string]kindRules, 4)}
+	for kind, rs := range map[string]RuleSet{
+		"network": doc.Network,
+		"fs":      doc.FS,
+		"env":     doc.Env,
+		"exec":    doc.Exec,
+	} {
+		kr, err := compileRuleSet(rs)
+		if err != nil {
+			return nil, fmt.Errorf("capability: %s: %w", kind, err)
+		}
+		m.rules[kind] = kr
+	}
+	return m, nil
+}
+
+func compileRuleSet(rs RuleSet) (kindRules, error) {
+	var kr kindRules
+	for _, raw := range rs.Allow {
+		p, err := Compile(raw)
+		if err != nil {
+			return kindRules{}, err
+		}
+		kr.allow = append(kr.allow, p)
+	}
+	for _, raw := range rs.Deny {
+		p, err := Compile(raw)
+		if err != nil {
+			return kindRules{}, err
+		}
+		kr.deny = append(kr.deny, p)
+	}
+	return kr, nil
+}
+
+// Decide evaluates subject against kind's deny list, then its allow list.
+// matched reports whether any rule (allow or deny) fired at all; allowed is
+// only meaningful when matched is true. Deny always wins over allow when a
+// subject matches both lists. pattern is the specific rule that decided the
+// outcome, for CapabilityDeniedError.Pattern.
+func (m *Matcher) Decide(kind, subject string) (allowed, matched bool, pattern string) {
+	if m == nil {
+		return false, false, ""
+	}
+	kr, ok := m.rules[kind]
+	if !ok {
+		return false, false, ""
+	}
+	for _, p := range kr.deny {
+		if p.Match(subject) {
+			return false, true, p.String()
+		}
+	}
+	for _, p := range kr.allow {
+		if p.Match(subject) {
+			return true, true, p.String()
+		}
+	}
+	return false, false, ""
+}
+
+// ExpandIntent splits a wildcard intent string such as
+// "network:outbound:tcp:443" or "fs:read:/etc/*" into the kind Decide
+// expects ("network", "fs") and the subject to match against it, so policy
+// authors can reason about the grant surface in terms of intents rather
+// than CapabilityChecker's internal request structs. Everything after the
+// first segment is rejoined with ":" as the subject, since subjects like
+// "host:port" or a regex pattern may themselves contain colons.
+func ExpandIntent(intent string) (kind, subject string, ok bool) {
+	parts := strings.SplitN(intent, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
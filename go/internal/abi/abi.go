@@ -3,7 +3,11 @@
 package abi
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"unsafe"
 )
@@ -11,13 +15,66 @@ import (
 // MemoryManager tracks all allocations made by the SDK in WASM linear memory.
 // It keeps a reference to allocated slices to prevent the Go GC from collecting them,
 // effectively "pinning" the memory until explicitly freed or during panic recovery.
+// total and limit bound the sum of all currently-pinned allocations; limit of
+// 0 means unbounded (the out-of-the-box behavior).
 var memoryManager = struct {
 	sync.Mutex
-	ptrs map[uint32][]byte // ptr -> slice reference
+	ptrs  map[uint32][]byte // ptr -> slice reference
+	total int               // sum of len(buf) across ptrs
+	limit int               // 0 = unbounded
 }{ptrs: make(map[uint32][]byte)}
 
+// MemoryError mirrors the shape of the SDK's own MemoryError (go/errors.go),
+// which abi can't import directly - the sdk package already imports abi, so
+// the reverse import would cycle.
+type MemoryError struct {
+	Requested int // Requested allocation size
+	Current   int // Current total allocated
+	Limit     int // Maximum allowed
+}
+
+func (e *MemoryError) Error() string {
+	return fmt.Sprintf("abi: memory allocation failed: requested %d bytes, current %d bytes, limit %d bytes",
+		e.Requested, e.Current, e.Limit)
+}
+
+// lastAllocErr is the sticky error LastAllocError reports, set whenever
+// allocate refuses a request over the configured limit and cleared by the
+// next successful allocate.
+var lastAllocErr struct {
+	sync.Mutex
+	err error
+}
+
+// SetMemoryLimit bounds the total size of all memory allocate is allowed to
+// have pinned at once, across every call, to bytes. A limit of 0 (the
+// default) leaves allocation unbounded.
+func SetMemoryLimit(bytes int) {
+	memoryManager.Lock()
+	memoryManager.limit = bytes
+	memoryManager.Unlock()
+}
+
+// LastAllocError returns the *MemoryError from the most recent allocate
+// call that was refused for exceeding the configured limit, or nil if the
+// most recent allocate (if any) succeeded.
+func LastAllocError() error {
+	lastAllocErr.Lock()
+	defer lastAllocErr.Unlock()
+	return lastAllocErr.err
+}
+
+func setLastAllocError(err error) {
+	lastAllocErr.Lock()
+	lastAllocErr.err = err
+	lastAllocErr.Unlock()
+}
+
 // allocate reserves memory in the WASM linear memory and returns a pointer.
 // The host can read from this pointer. It tracks the allocation to prevent GC.
+// If memoryManager.limit is set and size would push the pinned total over
+// it, allocate refuses the request, records a *MemoryError retrievable via
+// LastAllocError, and returns 0 without allocating.
 //
 //go:wasmexport allocate
 func allocate(size uint32) uint32 {
@@ -25,6 +82,16 @@ func allocate(size uint32) uint32 {
 		return 0
 	}
 
+	memoryManager.Lock()
+	if memoryManager.limit > 0 && memoryManager.total+int(size) > memoryManager.limit {
+		current, limit := memoryManager.total, memoryManager.limit
+		memoryManager.Unlock()
+		setLastAllocError(&MemoryError{Requested: int(size), Current: current, Limit: limit})
+		return 0
+	}
+	memoryManager.total += int(size)
+	memoryManager.Unlock()
+
 	buf := make([]byte, size)
 	ptr := uint32(uintptr(unsafe.Pointer(&buf[0])))
 
@@ -32,53 +99,421 @@ func allocate(size uint32) uint32 {
 	memoryManager.ptrs[ptr] = buf // PIN THE MEMORY: Store the slice to prevent GC
 	memoryManager.Unlock()
 
+	setLastAllocError(nil)
 	return ptr
 }
 
 // deallocate frees memory by removing the reference from the memory manager,
-// allowing the Go GC to collect it.
+// allowing the Go GC to collect it, and gives back the headroom it held
+// against memoryManager.limit.
 //
 //go:wasmexport deallocate
 func deallocate(ptr uint32, size uint32) {
 	memoryManager.Lock()
-	delete(memoryManager.ptrs, ptr)
+	if buf, ok := memoryManager.ptrs[ptr]; ok {
+		delete(memoryManager.ptrs, ptr)
+		memoryManager.total -= len(buf)
+	}
 	memoryManager.Unlock()
 }
 
-// FreeAllTracked frees all memory currently tracked by the SDK.
+// FreeAllTracked frees all memory currently tracked by the SDK, releasing
+// all the headroom it held against memoryManager.limit.
 // This is typically called during panic recovery or module shutdown to prevent leaks.
 func FreeAllTracked() {
 	memoryManager.Lock()
 	for ptr := range memoryManager.ptrs {
 		delete(memoryManager.ptrs, ptr)
 	}
+	memoryManager.total = 0
 	memoryManager.Unlock()
 }
 
+// Limits bounds the size of a single message crossing the guest/host ABI
+// boundary, the same role gRPC's MaxReceivedMessageSize plays for an RPC
+// call: without it, a malicious or buggy peer can force an allocation of
+// arbitrary size in WASM linear memory.
+type Limits struct {
+	MaxRequestBytes  int // Largest payload PtrFromBytes will pack for the host.
+	MaxResponseBytes int // Largest payload BytesFromPtr will read back from the host.
+}
+
+// defaultMaxMessageBytes is the out-of-the-box limit for both directions,
+// matching the order of magnitude gRPC defaults to (4 MiB).
+const defaultMaxMessageBytes = 4 * 1024 * 1024
+
+// DefaultLimits is the Limits value the SDK enforces until SetLimits is
+// called.
+var DefaultLimits = Limits{
+	MaxRequestBytes:  defaultMaxMessageBytes,
+	MaxResponseBytes: defaultMaxMessageBytes,
+}
+
+var currentLimits = DefaultLimits
+
+// SetLimits overrides the message size limits PtrFromBytes and BytesFromPtr
+// enforce. Plugin authors with unusually large legitimate payloads (e.g. a
+// check that streams a large file) can call this once during init; most
+// plugins should leave DefaultLimits in place.
+func SetLimits(l Limits) {
+	currentLimits = l
+}
+
+// ErrMessageTooLarge is returned by PtrFromBytes or BytesFromPtr when a
+// payload exceeds the configured Limits, instead of allocating it.
+var ErrMessageTooLarge = errors.New("abi: message exceeds configured size limit")
+
 // PtrFromBytes allocates WASM memory, copies the given data into it,
 // and returns the packed pointer and length (uint64).
 // The allocated memory is tracked by the SDK for later deallocation.
 // This is used when the Guest (plugin) sends data to the Host.
-func PtrFromBytes(data []byte) uint64 {
+// It returns ErrMessageTooLarge without allocating if data exceeds
+// currentLimits.MaxRequestBytes.
+func PtrFromBytes(data []byte) (uint64, error) {
 	if len(data) == 0 {
-		return 0
+		return 0, nil
+	}
+	if currentLimits.MaxRequestBytes > 0 && len(data) > currentLimits.MaxRequestBytes {
+		return 0, fmt.Errorf("%w: %d bytes (limit %d)", ErrMessageTooLarge, len(data), currentLimits.MaxRequestBytes)
 	}
 	size := uint32(len(data))
 	ptr := allocate(size)
+	if ptr == 0 {
+		// allocate refused the request over memoryManager.limit. Return the
+		// packed zero value (ptr=0, length=0) rather than calling
+		// PackPtrLen(0, size), which would panic on a null pointer with a
+		// non-zero length - this is the one case where that combination is
+		// legitimate, not a bug, so the host side can tell an OOM apart
+		// from an empty payload by checking the returned error.
+		return 0, LastAllocError()
+	}
 	copyToMemory(ptr, data)
-	return PackPtrLen(ptr, size)
+	return PackPtrLen(ptr, size), nil
 }
 
 // BytesFromPtr unpacks a uint64 into a pointer and length, then reads
 // the corresponding data from WASM linear memory.
 // The memory must have been allocated by the Host for the Guest to read.
 // This is used when the Guest receives data from the Host.
-func BytesFromPtr(packed uint64) []byte {
+// It returns ErrMessageTooLarge without reading memory if the length
+// UnpackPtrLen reports exceeds currentLimits.MaxResponseBytes - this guards
+// against a host claiming an oversized response before the guest copies it.
+func BytesFromPtr(packed uint64) ([]byte, error) {
 	ptr, length := UnpackPtrLen(packed)
 	if ptr == 0 || length == 0 {
-		return nil
+		return nil, nil
 	}
-	return readFromMemory(ptr, length)
+	if currentLimits.MaxResponseBytes > 0 && int(length) > currentLimits.MaxResponseBytes {
+		return nil, fmt.Errorf("%w: %d bytes (limit %d)", ErrMessageTooLarge, length, currentLimits.MaxResponseBytes)
+	}
+	return readFromMemory(ptr, length), nil
+}
+
+// HostCallError is returned by Call when the guest-side round trip to a host
+// function panics instead of returning normally (e.g. a marshal bug, or a
+// bounds error while reading the host's response out of linear memory).
+// Surfacing it as a typed error lets adapters return a normal Go error to
+// the plugin instead of aborting the whole WASM instance.
+type HostCallError struct {
+	Function  string // Name of the host function being called, e.g. "host_tcp_connect".
+	Recovered any    // The recovered panic value.
+}
+
+func (e *HostCallError) Error() string {
+	return fmt.Sprintf("abi: panic calling host function %q: %v", e.Function, e.Recovered)
+}
+
+// Call wraps a single guest-to-host round trip with panic recovery, modeled
+// on a gRPC recovery interceptor: it packs req into linear memory, invokes
+// fn with the packed pointer, and unpacks fn's result. If anything between
+// those steps panics (including inside fn itself), Call recovers, frees only
+// the memory this call allocated, and returns a *HostCallError instead of
+// letting the panic unwind into the plugin's own code.
+//
+// We deliberately free just this call's two allocations rather than calling
+// the package-wide FreeAllTracked, which would yank memory out from under
+// any other host call racing on another goroutine.
+func Call(name string, req []byte, fn func(uint64) uint64) (resp []byte, err error) {
+	var tracked []uint64
+	defer func() {
+		if r := recover(); r != nil {
+			for _, packed := range tracked {
+				DeallocatePacked(packed)
+			}
+			resp = nil
+			err = &HostCallError{Function: name, Recovered: r}
+		}
+	}()
+
+	reqPacked, err := PtrFromBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	tracked = append(tracked, reqPacked)
+
+	respPacked := fn(reqPacked)
+	tracked = append(tracked, respPacked)
+
+	resp, err = BytesFromPtr(respPacked)
+	if err != nil {
+		for _, packed := range tracked {
+			DeallocatePacked(packed)
+		}
+		tracked = nil
+		return nil, err
+	}
+
+	DeallocatePacked(reqPacked)
+	DeallocatePacked(respPacked)
+	tracked = nil
+
+	return resp, nil
+}
+
+// Codec identifies how a payload crossing the ABI boundary is compressed.
+// It doubles as the one-byte frame header PtrFromBytesCompressed prepends
+// and BytesFromPtrCompressed strips.
+type Codec byte
+
+const (
+	// CodecNone sends a payload uncompressed, just behind the frame header.
+	CodecNone Codec = 0x00
+	// CodecGzip compresses a payload with compress/gzip.
+	CodecGzip Codec = 0x01
+	// CodecZstd names the zstd codec for wire-format compatibility with
+	// hosts that support it; this package has no zstd compressor of its
+	// own (it would require an external dependency this module doesn't
+	// vendor), so compress/decompress reject it with ErrUnsupportedCodec.
+	CodecZstd Codec = 0x02
+)
+
+// compressionThreshold is the payload size above which PtrFromBytesCompressed
+// bothers compressing at all; below it, the framing and compression
+// overhead isn't worth paying.
+const compressionThreshold = 16 * 1024
+
+// ErrUnsupportedCodec is returned when a payload's frame header, or a
+// requested Codec, names a codec this package has no compressor for.
+var ErrUnsupportedCodec = errors.New("abi: unsupported compression codec")
+
+// CallOptions configures per-call-site behavior for CallWithOptions. The
+// zero value (Compression: CodecNone) never compresses, but unlike Call the
+// payload still carries the one-byte frame header.
+type CallOptions struct {
+	// Compression is the codec to use for payloads over compressionThreshold.
+	// It is downgraded to CodecNone by EffectiveCodec if the host hasn't
+	// advertised support for it via NegotiateCodecs.
+	Compression Codec
+}
+
+var (
+	negotiateOnce    sync.Once
+	negotiatedCodecs uint32 // bitmask; bit (1 << codec) set means the host advertised support for it
+)
+
+// host_negotiate_codecs asks the host which Codec values it can decompress,
+// returned as a bitmask (bit (1 << codec) set = supported).
+//
+//go:wasmimport reglet_host negotiate_codecs
+func host_negotiate_codecs() uint32
+
+// NegotiateCodecs asks the host which compression codecs it supports and
+// caches the result for EffectiveCodec. Register calls this once during
+// plugin startup; it is safe to call more than once; later calls are no-ops.
+func NegotiateCodecs() {
+	negotiateOnce.Do(func() {
+		negotiatedCodecs = host_negotiate_codecs()
+	})
+}
+
+// EffectiveCodec returns requested if NegotiateCodecs has recorded host
+// support for it, and CodecNone otherwise - compressing a payload the host
+// can't decompress would just break the call it was meant to shrink.
+func EffectiveCodec(requested Codec) Codec {
+	if requested == CodecNone {
+		return CodecNone
+	}
+	if negotiatedCodecs&(1<<uint(requested)) == 0 {
+		return CodecNone
+	}
+	return requested
+}
+
+// PtrFromBytesCompressed behaves like PtrFromBytes, except the packed bytes
+// are always prefixed with a one-byte Codec frame header. Once data exceeds
+// compressionThreshold and codec is not CodecNone, data is compressed with
+// codec first. BytesFromPtrCompressed reverses both steps.
+func PtrFromBytesCompressed(data []byte, codec Codec) (uint64, error) {
+	if codec == CodecNone || len(data) <= compressionThreshold {
+		return PtrFromBytes(append([]byte{byte(CodecNone)}, data...))
+	}
+	compressed, err := compressPayload(codec, data)
+	if err != nil {
+		return 0, err
+	}
+	return PtrFromBytes(append([]byte{byte(codec)}, compressed...))
+}
+
+// BytesFromPtrCompressed behaves like BytesFromPtr, then strips the one-byte
+// Codec frame header PtrFromBytesCompressed prepended, decompressing the
+// remainder if the header names a codec other than CodecNone.
+func BytesFromPtrCompressed(packed uint64) ([]byte, error) {
+	framed, err := BytesFromPtr(packed)
+	if err != nil {
+		return nil, err
+	}
+	if len(framed) == 0 {
+		return framed, nil
+	}
+	return decompressPayload(Codec(framed[0]), framed[1:])
+}
+
+// compressPayload compresses data with codec.
+func compressPayload(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecNone:
+		return data, nil
+	case CodecGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("abi: gzip compress: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("abi: gzip compress: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w: %#x", ErrUnsupportedCodec, byte(codec))
+	}
+}
+
+// decompressPayload reverses compressPayload.
+func decompressPayload(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecNone:
+		return data, nil
+	case CodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("abi: gzip decompress: %w", err)
+		}
+		defer r.Close()
+		out, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("abi: gzip decompress: %w", err)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%w: %#x", ErrUnsupportedCodec, byte(codec))
+	}
+}
+
+// CallWithOptions behaves like Call, except the request and response are
+// framed and, once over compressionThreshold, compressed per
+// EffectiveCodec(opts.Compression). Use this for bulk endpoints (KV list,
+// HTTP body, exec stdout); latency-sensitive small calls should keep using
+// Call, which never pays the framing byte or negotiation check.
+func CallWithOptions(name string, req []byte, fn func(uint64) uint64, opts CallOptions) (resp []byte, err error) {
+	var tracked []uint64
+	defer func() {
+		if r := recover(); r != nil {
+			for _, packed := range tracked {
+				DeallocatePacked(packed)
+			}
+			resp = nil
+			err = &HostCallError{Function: name, Recovered: r}
+		}
+	}()
+
+	reqPacked, err := PtrFromBytesCompressed(req, EffectiveCodec(opts.Compression))
+	if err != nil {
+		return nil, err
+	}
+	tracked = append(tracked, reqPacked)
+
+	respPacked := fn(reqPacked)
+	tracked = append(tracked, respPacked)
+
+	resp, err = BytesFromPtrCompressed(respPacked)
+	if err != nil {
+		for _, packed := range tracked {
+			DeallocatePacked(packed)
+		}
+		tracked = nil
+		return nil, err
+	}
+
+	DeallocatePacked(reqPacked)
+	DeallocatePacked(respPacked)
+	tracked = nil
+
+	return resp, nil
+}
+
+// StreamWindow is a fixed-size buffer in WASM linear memory that a chunked
+// host function (e.g. host_tcp_stream_next) writes successive chunks into,
+// so a transfer larger than Limits.MaxResponseBytes - a long TCP read, a
+// large file - can be pulled across the ABI boundary window by window
+// instead of needing one allocation sized to the whole transfer.
+type StreamWindow struct {
+	ptr  uint32
+	size uint32
+}
+
+// NewStreamWindow allocates a window of size bytes. Pass Ptr() to whatever
+// host call opens the stream (e.g. host_tcp_stream_open), which should write
+// each subsequent chunk directly into this window rather than allocating
+// its own response buffer per chunk. It returns an error, without allocating
+// a window, if allocate refuses the request for exceeding a configured
+// SetMemoryLimit - see LastAllocError.
+func NewStreamWindow(size int) (*StreamWindow, error) {
+	ptr := allocate(uint32(size))
+	if ptr == 0 {
+		return nil, LastAllocError()
+	}
+	return &StreamWindow{ptr: ptr, size: uint32(size)}, nil
+}
+
+// Ptr returns the window's address in linear memory.
+func (w *StreamWindow) Ptr() uint32 {
+	return w.ptr
+}
+
+// Close releases the window. Call it once the stream is fully drained.
+func (w *StreamWindow) Close() {
+	deallocate(w.ptr, w.size)
+}
+
+// Next calls fn - typically a thin wrapper around a streaming host function
+// like host_tcp_stream_next(handle) - and returns the chunk it wrote into
+// the window. fn's packed result follows PackStreamHeader: a sequence
+// number in the high 32 bits and, in the low 32 bits, the number of bytes
+// written into the window. A length of 0 signals end of stream (mirroring
+// io.Reader's EOF convention), in which case Next returns (nil, seq, true,
+// nil). Only length bytes are copied out of the window, so draining a
+// multi-megabyte transfer never requires an allocation bigger than size.
+func (w *StreamWindow) Next(fn func(windowPtr uint32) uint64) (chunk []byte, seq uint32, done bool, err error) {
+	seq, length := UnpackStreamHeader(fn(w.ptr))
+	if length == 0 {
+		return nil, seq, true, nil
+	}
+	if length > w.size {
+		return nil, seq, false, fmt.Errorf("%w: host wrote %d bytes into a %d byte stream window", ErrMessageTooLarge, length, w.size)
+	}
+	return readFromMemory(w.ptr, length), seq, false, nil
+}
+
+// PackStreamHeader packs a sequence number and chunk length into the uint64
+// a streaming host function returns: seq in the high 32 bits, length in the
+// low 32 bits.
+func PackStreamHeader(seq, length uint32) uint64 {
+	return (uint64(seq) << 32) | uint64(length)
+}
+
+// UnpackStreamHeader reverses PackStreamHeader.
+func UnpackStreamHeader(packed uint64) (seq, length uint32) {
+	return uint32(packed >> 32), uint32(packed)
 }
 
 // DeallocatePacked unpacks a uint64 pointer/length and deallocates the memory.
@@ -129,4 +564,4 @@ func readFromMemory(ptr uint32, length uint32) []byte {
 	data := make([]byte, length) // Create a new slice to return a copy
 	copy(data, src)
 	return data
-}
\ No newline at end of file
+}
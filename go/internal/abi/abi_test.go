@@ -3,6 +3,8 @@
 package abi
 
 import (
+	"bytes"
+	"errors"
 	"sync"
 	"testing"
 )
@@ -120,7 +122,10 @@ func TestFreeAllTracked(t *testing.T) {
 
 func TestPtrFromBytes(t *testing.T) {
 	data := []byte("test data")
-	packed := PtrFromBytes(data)
+	packed, err := PtrFromBytes(data)
+	if err != nil {
+		t.Fatalf("PtrFromBytes returned error: %v", err)
+	}
 
 	_, length := UnpackPtrLen(packed)
 	if length != uint32(len(data)) {
@@ -128,7 +133,10 @@ func TestPtrFromBytes(t *testing.T) {
 	}
 
 	// Check content
-	readData := BytesFromPtr(packed)
+	readData, err := BytesFromPtr(packed)
+	if err != nil {
+		t.Fatalf("BytesFromPtr returned error: %v", err)
+	}
 	if string(readData) != string(data) {
 		t.Errorf("BytesFromPtr = %q; want %q", readData, data)
 	}
@@ -136,6 +144,273 @@ func TestPtrFromBytes(t *testing.T) {
 	DeallocatePacked(packed)
 }
 
+func TestPtrFromBytes_RejectsOversizedRequest(t *testing.T) {
+	currentLimits = Limits{MaxRequestBytes: 16, MaxResponseBytes: DefaultLimits.MaxResponseBytes}
+	defer func() { currentLimits = DefaultLimits }()
+
+	_, err := PtrFromBytes(make([]byte, 17))
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("PtrFromBytes error = %v; want ErrMessageTooLarge", err)
+	}
+}
+
+func TestBytesFromPtr_RejectsOversizedResponse(t *testing.T) {
+	currentLimits = Limits{MaxRequestBytes: DefaultLimits.MaxRequestBytes, MaxResponseBytes: 16}
+	defer func() { currentLimits = DefaultLimits }()
+
+	ptr := allocate(17)
+	defer deallocate(ptr, 17)
+
+	_, err := BytesFromPtr(PackPtrLen(ptr, 17))
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("BytesFromPtr error = %v; want ErrMessageTooLarge", err)
+	}
+}
+
+func TestStreamWindow_ReassemblesChunksUntilEOF(t *testing.T) {
+	window, err := NewStreamWindow(8)
+	if err != nil {
+		t.Fatalf("NewStreamWindow: %v", err)
+	}
+	defer window.Close()
+
+	chunks := [][]byte{[]byte("abcd"), []byte("efgh"), {}}
+	call := 0
+	next := func(windowPtr uint32) uint64 {
+		data := chunks[call]
+		call++
+		copyToMemory(windowPtr, data)
+		return PackStreamHeader(uint32(call-1), uint32(len(data)))
+	}
+
+	var got []byte
+	for i := 0; i < len(chunks); i++ {
+		chunk, seq, done, err := window.Next(next)
+		if err != nil {
+			t.Fatalf("Next returned error: %v", err)
+		}
+		if done {
+			if i != len(chunks)-1 {
+				t.Fatalf("Next signaled done early at call %d", i)
+			}
+			break
+		}
+		if int(seq) != i {
+			t.Errorf("Next seq = %d; want %d", seq, i)
+		}
+		got = append(got, chunk...)
+	}
+
+	if string(got) != "abcdefgh" {
+		t.Errorf("reassembled stream = %q; want %q", got, "abcdefgh")
+	}
+}
+
+func TestStreamWindow_ChunkLargerThanWindowErrors(t *testing.T) {
+	window, err := NewStreamWindow(4)
+	if err != nil {
+		t.Fatalf("NewStreamWindow: %v", err)
+	}
+	defer window.Close()
+
+	_, _, _, err = window.Next(func(uint32) uint64 {
+		return PackStreamHeader(0, 5)
+	})
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("Next error = %v; want ErrMessageTooLarge", err)
+	}
+}
+
+func TestNewStreamWindow_ReturnsErrorOnOOM(t *testing.T) {
+	FreeAllTracked()
+	SetMemoryLimit(4)
+	defer SetMemoryLimit(0)
+
+	window, err := NewStreamWindow(8)
+	if window != nil {
+		t.Errorf("NewStreamWindow over the limit returned %v; want nil", window)
+	}
+	var memErr *MemoryError
+	if !errors.As(err, &memErr) {
+		t.Fatalf("NewStreamWindow error = %v; want *MemoryError", err)
+	}
+}
+
+func TestCall_Success(t *testing.T) {
+	FreeAllTracked()
+
+	echo := func(packed uint64) uint64 {
+		data, _ := BytesFromPtr(packed)
+		out, _ := PtrFromBytes(data)
+		return out
+	}
+
+	resp, err := Call("host_echo", []byte("hello"), echo)
+	if err != nil {
+		t.Fatalf("Call returned error: %v", err)
+	}
+	if string(resp) != "hello" {
+		t.Errorf("Call response = %q; want %q", resp, "hello")
+	}
+
+	memoryManager.Lock()
+	tracked := len(memoryManager.ptrs)
+	memoryManager.Unlock()
+	if tracked != 0 {
+		t.Errorf("expected 0 tracked pointers after a successful Call, got %d", tracked)
+	}
+}
+
+func TestCall_RecoversPanicAndFreesOnlyItsOwnAllocations(t *testing.T) {
+	FreeAllTracked()
+
+	// A pointer allocated outside the call under test; Call must not touch it.
+	outside := allocate(16)
+
+	panicking := func(packed uint64) uint64 {
+		panic("host function exploded")
+	}
+
+	resp, err := Call("host_boom", []byte("hello"), panicking)
+	if resp != nil {
+		t.Errorf("Call returned non-nil response on panic: %v", resp)
+	}
+
+	var hostErr *HostCallError
+	if !errors.As(err, &hostErr) {
+		t.Fatalf("Call error = %v; want *HostCallError", err)
+	}
+	if hostErr.Function != "host_boom" {
+		t.Errorf("HostCallError.Function = %q; want %q", hostErr.Function, "host_boom")
+	}
+
+	memoryManager.Lock()
+	_, stillTracked := memoryManager.ptrs[outside]
+	tracked := len(memoryManager.ptrs)
+	memoryManager.Unlock()
+
+	if !stillTracked {
+		t.Errorf("Call's panic recovery freed a pointer it did not allocate")
+	}
+	if tracked != 1 {
+		t.Errorf("expected only the pre-existing pointer to remain tracked, got %d tracked", tracked)
+	}
+
+	deallocate(outside, 16)
+}
+
+func TestPtrFromBytesCompressed_RoundTripsBelowThreshold(t *testing.T) {
+	data := []byte("small payload")
+	packed, err := PtrFromBytesCompressed(data, CodecGzip)
+	if err != nil {
+		t.Fatalf("PtrFromBytesCompressed returned error: %v", err)
+	}
+
+	got, err := BytesFromPtrCompressed(packed)
+	if err != nil {
+		t.Fatalf("BytesFromPtrCompressed returned error: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("round trip = %q; want %q", got, data)
+	}
+
+	// Below compressionThreshold, the frame header should be CodecNone even
+	// though CodecGzip was requested - compressing a few bytes isn't worth it.
+	raw, err := BytesFromPtr(packed)
+	if err != nil {
+		t.Fatalf("BytesFromPtr returned error: %v", err)
+	}
+	if Codec(raw[0]) != CodecNone {
+		t.Errorf("frame header = %#x; want CodecNone for a small payload", raw[0])
+	}
+}
+
+func TestPtrFromBytesCompressed_CompressesAboveThreshold(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), compressionThreshold+1)
+	packed, err := PtrFromBytesCompressed(data, CodecGzip)
+	if err != nil {
+		t.Fatalf("PtrFromBytesCompressed returned error: %v", err)
+	}
+
+	raw, err := BytesFromPtr(packed)
+	if err != nil {
+		t.Fatalf("BytesFromPtr returned error: %v", err)
+	}
+	if Codec(raw[0]) != CodecGzip {
+		t.Fatalf("frame header = %#x; want CodecGzip", raw[0])
+	}
+	if len(raw)-1 >= len(data) {
+		t.Errorf("compressed payload (%d bytes) is not smaller than the original (%d bytes)", len(raw)-1, len(data))
+	}
+
+	got, err := BytesFromPtrCompressed(packed)
+	if err != nil {
+		t.Fatalf("BytesFromPtrCompressed returned error: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("decompressed round trip did not match original")
+	}
+}
+
+func TestBytesFromPtrCompressed_UnsupportedCodecErrors(t *testing.T) {
+	packed, err := PtrFromBytes([]byte{byte(CodecZstd), 'x', 'y', 'z'})
+	if err != nil {
+		t.Fatalf("PtrFromBytes returned error: %v", err)
+	}
+
+	_, err = BytesFromPtrCompressed(packed)
+	if !errors.Is(err, ErrUnsupportedCodec) {
+		t.Fatalf("BytesFromPtrCompressed error = %v; want ErrUnsupportedCodec", err)
+	}
+}
+
+func TestEffectiveCodec_DowngradesWithoutNegotiation(t *testing.T) {
+	old := negotiatedCodecs
+	negotiatedCodecs = 0
+	defer func() { negotiatedCodecs = old }()
+
+	if got := EffectiveCodec(CodecGzip); got != CodecNone {
+		t.Errorf("EffectiveCodec(CodecGzip) = %v; want CodecNone when the host hasn't negotiated it", got)
+	}
+}
+
+func TestEffectiveCodec_AllowsNegotiatedCodec(t *testing.T) {
+	old := negotiatedCodecs
+	negotiatedCodecs = 1 << uint(CodecGzip)
+	defer func() { negotiatedCodecs = old }()
+
+	if got := EffectiveCodec(CodecGzip); got != CodecGzip {
+		t.Errorf("EffectiveCodec(CodecGzip) = %v; want CodecGzip once the host has negotiated it", got)
+	}
+}
+
+func TestCallWithOptions_CompressesAboveThreshold(t *testing.T) {
+	FreeAllTracked()
+	old := negotiatedCodecs
+	negotiatedCodecs = 1 << uint(CodecGzip)
+	defer func() { negotiatedCodecs = old }()
+
+	data := bytes.Repeat([]byte("y"), compressionThreshold+1)
+
+	var sawCompressedHeader bool
+	echo := func(packed uint64) uint64 {
+		raw, _ := BytesFromPtr(packed)
+		sawCompressedHeader = Codec(raw[0]) == CodecGzip
+		return packed // the "host" echoes the same framed+compressed bytes back
+	}
+
+	resp, err := CallWithOptions("host_echo", data, echo, CallOptions{Compression: CodecGzip})
+	if err != nil {
+		t.Fatalf("CallWithOptions returned error: %v", err)
+	}
+	if !sawCompressedHeader {
+		t.Errorf("CallWithOptions sent an uncompressed frame for a payload over compressionThreshold")
+	}
+	if string(resp) != string(data) {
+		t.Errorf("CallWithOptions response did not match the original payload")
+	}
+}
+
 func TestConcurrency(t *testing.T) {
 	// Reset
 	FreeAllTracked()
@@ -147,9 +422,9 @@ func TestConcurrency(t *testing.T) {
 	for i := 0; i < count; i++ {
 		go func() {
 			defer wg.Done()
-			packed := PtrFromBytes([]byte("concurrent"))
+			packed, _ := PtrFromBytes([]byte("concurrent"))
 			// Simulate some work
-			_ = BytesFromPtr(packed)
+			_, _ = BytesFromPtr(packed)
 			DeallocatePacked(packed)
 		}()
 	}
@@ -163,3 +438,107 @@ func TestConcurrency(t *testing.T) {
 		t.Errorf("race condition? expected 0 tracked pointers, got %d", tracked)
 	}
 }
+
+func TestAllocate_RefusesOverLimit(t *testing.T) {
+	FreeAllTracked()
+	SetMemoryLimit(100)
+	defer SetMemoryLimit(0)
+
+	ptr := allocate(50)
+	if ptr == 0 {
+		t.Fatalf("allocate(50) against a 100-byte limit returned 0")
+	}
+	defer deallocate(ptr, 50)
+
+	refused := allocate(51)
+	if refused != 0 {
+		t.Fatalf("allocate(51) with 50 already pinned against a 100-byte limit = %d; want 0", refused)
+	}
+
+	var memErr *MemoryError
+	if !errors.As(LastAllocError(), &memErr) {
+		t.Fatalf("LastAllocError() = %v; want *MemoryError", LastAllocError())
+	}
+	if memErr.Requested != 51 || memErr.Current != 50 || memErr.Limit != 100 {
+		t.Errorf("LastAllocError() = %+v; want {Requested:51 Current:50 Limit:100}", memErr)
+	}
+}
+
+func TestPtrFromBytes_ReturnsZeroPackedOnOOM(t *testing.T) {
+	FreeAllTracked()
+	SetMemoryLimit(4)
+	defer SetMemoryLimit(0)
+
+	packed, err := PtrFromBytes([]byte("too big"))
+	if packed != 0 {
+		t.Errorf("PtrFromBytes over the limit returned packed = %d; want 0", packed)
+	}
+	var memErr *MemoryError
+	if !errors.As(err, &memErr) {
+		t.Fatalf("PtrFromBytes error = %v; want *MemoryError", err)
+	}
+}
+
+func TestAllocate_ConcurrentNearLimitDoesNotDoubleAccount(t *testing.T) {
+	FreeAllTracked()
+	SetMemoryLimit(1000)
+	defer SetMemoryLimit(0)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var granted []uint32
+	count := 50
+
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func() {
+			defer wg.Done()
+			if ptr := allocate(30); ptr != 0 {
+				mu.Lock()
+				granted = append(granted, ptr)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	memoryManager.Lock()
+	total := memoryManager.total
+	tracked := len(memoryManager.ptrs)
+	memoryManager.Unlock()
+
+	if total > 1000 {
+		t.Errorf("memoryManager.total = %d after concurrent allocate calls; want <= 1000 (limit)", total)
+	}
+	if tracked != len(granted) {
+		t.Errorf("tracked pointer count = %d; want %d (one per successful allocate)", tracked, len(granted))
+	}
+	if total != tracked*30 {
+		t.Errorf("memoryManager.total = %d; want %d (30 bytes per tracked pointer, no double-accounting)", total, tracked*30)
+	}
+
+	for _, ptr := range granted {
+		deallocate(ptr, 30)
+	}
+}
+
+func TestFreeAllTracked_ReleasesHeadroom(t *testing.T) {
+	FreeAllTracked()
+	SetMemoryLimit(100)
+	defer SetMemoryLimit(0)
+
+	if ptr := allocate(90); ptr == 0 {
+		t.Fatalf("allocate(90) against a 100-byte limit returned 0")
+	}
+	if refused := allocate(20); refused != 0 {
+		t.Fatalf("allocate(20) with 90 already pinned against a 100-byte limit = %d; want 0", refused)
+	}
+
+	FreeAllTracked()
+
+	ptr := allocate(90)
+	if ptr == 0 {
+		t.Fatalf("allocate(90) after FreeAllTracked released the limit's headroom returned 0")
+	}
+	deallocate(ptr, 90)
+}
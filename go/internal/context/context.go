@@ -0,0 +1,258 @@
+//go:build wasip1
+
+// Package sdkcontext bridges context.Context across the WASM ABI boundary
+// in both directions. RunMetadata carries a deadline, trace ID, and tenant
+// from the host into a guest-side context.Context for _describe/_schema/
+// _observe to run under instead of context.Background(); ContextWireFormat
+// carries that context.Context back out to the host on every dns_lookup/
+// http_request/... call the guest makes, so host-side logging and tracing
+// can correlate a guest-initiated network call with the plugin invocation
+// that produced it.
+package sdkcontext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type contextKey int
+
+const (
+	traceIDKey contextKey = iota
+	tenantKey
+	spanContextKey
+	traceParentKey
+	traceStateKey
+)
+
+// TraceIDKey and TenantKey address the values FromRunMetadata stores on
+// the context.Context it returns; plugin code reads them with
+// ctx.Value(sdkcontext.TraceIDKey) / ctx.Value(sdkcontext.TenantKey).
+//
+// SpanContextKey, TraceParentKey, and TraceStateKey are the two ways a W3C
+// Trace Context can reach a guest-side context.Context - see
+// ExtractTraceContext.
+var (
+	TraceIDKey     = traceIDKey
+	TenantKey      = tenantKey
+	SpanContextKey = spanContextKey
+	TraceParentKey = traceParentKey
+	TraceStateKey  = traceStateKey
+)
+
+// SpanContext is a minimal OpenTelemetry-shaped span identity - just
+// TraceID and SpanID - so a plugin (or a future OTel-integrated host) can
+// hang a span off ctx without this package depending on an actual OTel
+// SDK. ExtractTraceContext prefers this over raw header strings when
+// both are present, since it's structured and can't disagree with itself
+// the way a malformed traceparent string could.
+type SpanContext struct {
+	TraceID string // 32 lowercase hex chars
+	SpanID  string // 16 lowercase hex chars
+}
+
+// WithSpanContext returns a copy of ctx carrying sc, for ExtractTraceContext
+// to build a traceparent header from later.
+func WithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, SpanContextKey, sc)
+}
+
+// WithTraceHeaders returns a copy of ctx carrying the raw traceparent and
+// tracestate header strings the host received on the request that
+// triggered this call, for ExtractTraceContext to forward unchanged.
+func WithTraceHeaders(ctx context.Context, traceparent, tracestate string) context.Context {
+	if traceparent != "" {
+		ctx = context.WithValue(ctx, TraceParentKey, traceparent)
+	}
+	if tracestate != "" {
+		ctx = context.WithValue(ctx, TraceStateKey, tracestate)
+	}
+	return ctx
+}
+
+// ExtractTraceContext returns the W3C Trace Context (traceparent,
+// tracestate) that outbound calls made under ctx should carry, so nested
+// plugin-originated HTTP/DNS calls link back into the trace that triggered
+// this plugin invocation instead of starting a disconnected one. It checks,
+// in order: a SpanContext set by WithSpanContext, raw header strings
+// stashed by the host via WithTraceHeaders, and - if neither is present -
+// synthesizes a fresh traceparent with a freshly generated trace and span
+// ID on every call. FromRunMetadata stores one synthesized traceparent on
+// the context it builds, so the outbound calls of a plugin invocation the
+// host didn't instrument still share one trace.
+func ExtractTraceContext(ctx context.Context) (traceparent, tracestate string) {
+	if sc, ok := ctx.Value(SpanContextKey).(SpanContext); ok && sc.TraceID != "" && sc.SpanID != "" {
+		return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID), ""
+	}
+	if tp, ok := ctx.Value(TraceParentKey).(string); ok && tp != "" {
+		ts, _ := ctx.Value(TraceStateKey).(string)
+		return tp, ts
+	}
+	return newTraceParent(), ""
+}
+
+// newTraceParent synthesizes a fresh W3C traceparent header
+// ("version-traceid-parentid-flags") with a random 16-byte trace ID and
+// 8-byte parent ID, flagged sampled (01).
+func newTraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", randomHex(16), randomHex(8))
+}
+
+// randomHex returns n random bytes hex-encoded.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b) // crypto/rand.Read on a fixed-size buffer never errors
+	return hex.EncodeToString(b)
+}
+
+// RunMetadata is the JSON wire format the host packs alongside a
+// _describe/_schema/_observe call, carrying everything the SDK needs to
+// build a real context.Context for that call instead of
+// context.Background(). CallID is assigned by the host, not the guest -
+// the guest has no way to report an ID back to the host before a
+// synchronous call completes, so the host must already know the ID it
+// intends to address with a later _cancel(callID) call.
+type RunMetadata struct {
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	TimeoutMs int64      `json:"timeout_ms,omitempty"`
+	RequestID string     `json:"request_id,omitempty"`
+	TraceID   string     `json:"trace_id,omitempty"`
+	Tenant    string     `json:"tenant,omitempty"`
+	CallID    uint64     `json:"call_id,omitempty"`
+
+	// TraceParent and TraceState are the raw W3C Trace Context headers off
+	// the request that triggered this call, if the host received one (e.g.
+	// a reverse-proxied HTTP request carrying them). FromRunMetadata stores
+	// them on the returned context.Context via WithTraceHeaders, so
+	// ExtractTraceContext can forward them on outbound HTTP/DNS calls
+	// instead of synthesizing a disconnected trace.
+	TraceParent string `json:"trace_parent,omitempty"`
+	TraceState  string `json:"trace_state,omitempty"`
+}
+
+// FromRunMetadata builds a context.Context carrying meta's trace ID and
+// tenant, bounded by meta's deadline (or, lacking one, its timeout). If meta
+// carries no TraceParent, a fresh one is synthesized once here and stored
+// on the context, so every outbound call made under it shares it. The
+// returned CancelFunc releases the context's resources exactly like any
+// other context.CancelFunc - callers must still call it even if they also
+// pass it to Register.
+func FromRunMetadata(meta RunMetadata) (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if meta.TraceID != "" {
+		ctx = context.WithValue(ctx, TraceIDKey, meta.TraceID)
+	}
+	if meta.Tenant != "" {
+		ctx = context.WithValue(ctx, TenantKey, meta.Tenant)
+	}
+	traceParent, traceState := meta.TraceParent, meta.TraceState
+	if traceParent == "" {
+		traceParent, traceState = newTraceParent(), ""
+	}
+	ctx = WithTraceHeaders(ctx, traceParent, traceState)
+
+	switch {
+	case meta.Deadline != nil:
+		return context.WithDeadline(ctx, *meta.Deadline)
+	case meta.TimeoutMs > 0:
+		return context.WithTimeout(ctx, time.Duration(meta.TimeoutMs)*time.Millisecond)
+	default:
+		return context.WithCancel(ctx)
+	}
+}
+
+// ContextWireFormat is the JSON wire format a guest reports its
+// context.Context as on every outbound host call (dns_lookup,
+// http_request, ...), so the host can correlate that call with the
+// plugin invocation, and the trace, that produced it.
+type ContextWireFormat struct {
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	TimeoutMs int64      `json:"timeout_ms,omitempty"`
+	RequestID string     `json:"request_id,omitempty"` // For log correlation
+	TraceID   string     `json:"trace_id,omitempty"`
+	Tenant    string     `json:"tenant,omitempty"`
+	Canceled  bool       `json:"canceled,omitempty"` // True if context is already canceled
+
+	// TraceParent and TraceState are the W3C Trace Context headers this
+	// call's outbound requests carry - see ExtractTraceContext. Host-side
+	// loggers/exporters can pick these up to correlate a guest-initiated
+	// network call with the trace it belongs to.
+	TraceParent string `json:"trace_parent,omitempty"`
+	TraceState  string `json:"trace_state,omitempty"`
+}
+
+// ContextToWire extracts ctx's deadline, trace ID, tenant, trace context,
+// and cancellation state into the wire format a guest sends alongside an
+// outbound call.
+func ContextToWire(ctx context.Context) ContextWireFormat {
+	var wire ContextWireFormat
+
+	if deadline, ok := ctx.Deadline(); ok {
+		wire.Deadline = &deadline
+		wire.TimeoutMs = time.Until(deadline).Milliseconds()
+	}
+	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
+		wire.TraceID = traceID
+	}
+	if tenant, ok := ctx.Value(TenantKey).(string); ok {
+		wire.Tenant = tenant
+	}
+	wire.TraceParent, wire.TraceState = ExtractTraceContext(ctx)
+	if ctx.Err() != nil {
+		wire.Canceled = true
+	}
+	return wire
+}
+
+// calls tracks the in-flight CancelFuncs Register has recorded, keyed by
+// the host-assigned CallID a later _cancel wasmexport uses to address
+// the right one.
+var (
+	callsMu sync.Mutex
+	calls   = map[uint64]context.CancelFunc{}
+)
+
+// Register records cancel as the CancelFunc for callID, so a later
+// Cancel(callID) reaches it. Registering callID 0 is a no-op: 0 means the
+// host didn't opt this call into cancellation.
+func Register(callID uint64, cancel context.CancelFunc) {
+	if callID == 0 {
+		return
+	}
+	callsMu.Lock()
+	calls[callID] = cancel
+	callsMu.Unlock()
+}
+
+// Cancel invokes and forgets the CancelFunc registered under callID,
+// reporting whether callID was still registered. This is what the
+// _cancel wasmexport calls when the host signals that a running call
+// should stop.
+func Cancel(callID uint64) bool {
+	callsMu.Lock()
+	cancel, ok := calls[callID]
+	delete(calls, callID)
+	callsMu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// Release forgets callID without invoking its CancelFunc, for a call
+// that finished on its own rather than being canceled. Callers still
+// need to call the CancelFunc itself, as context.Context requires -
+// Release only stops a later _cancel(callID) from reaching a call that
+// has already finished.
+func Release(callID uint64) {
+	if callID == 0 {
+		return
+	}
+	callsMu.Lock()
+	delete(calls, callID)
+	callsMu.Unlock()
+}
@@ -0,0 +1,155 @@
+//go:build wasip1
+
+package sdkcontext
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestFromRunMetadata_Deadline(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := FromRunMetadata(RunMetadata{Deadline: &deadline, TraceID: "trace-1", Tenant: "acme"})
+	defer cancel()
+
+	got, ok := ctx.Deadline()
+	if !ok || !got.Equal(deadline) {
+		t.Errorf("ctx.Deadline() = %v, %v; want %v, true", got, ok, deadline)
+	}
+	if v, _ := ctx.Value(TraceIDKey).(string); v != "trace-1" {
+		t.Errorf("ctx.Value(TraceIDKey) = %q; want %q", v, "trace-1")
+	}
+	if v, _ := ctx.Value(TenantKey).(string); v != "acme" {
+		t.Errorf("ctx.Value(TenantKey) = %q; want %q", v, "acme")
+	}
+}
+
+func TestFromRunMetadata_Timeout(t *testing.T) {
+	ctx, cancel := FromRunMetadata(RunMetadata{TimeoutMs: 50})
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Errorf("ctx.Deadline() ok = false; want true for a TimeoutMs-derived context")
+	}
+}
+
+func TestFromRunMetadata_NoDeadline(t *testing.T) {
+	ctx, cancel := FromRunMetadata(RunMetadata{})
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("ctx.Deadline() ok = true; want false for empty RunMetadata")
+	}
+}
+
+func TestContextToWire_RoundTrips(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := FromRunMetadata(RunMetadata{Deadline: &deadline, TraceID: "trace-2", Tenant: "acme"})
+	defer cancel()
+
+	wire := ContextToWire(ctx)
+	if wire.TraceID != "trace-2" {
+		t.Errorf("wire.TraceID = %q; want %q", wire.TraceID, "trace-2")
+	}
+	if wire.Tenant != "acme" {
+		t.Errorf("wire.Tenant = %q; want %q", wire.Tenant, "acme")
+	}
+	if wire.Deadline == nil || !wire.Deadline.Equal(deadline) {
+		t.Errorf("wire.Deadline = %v; want %v", wire.Deadline, deadline)
+	}
+	if wire.Canceled {
+		t.Errorf("wire.Canceled = true; want false for a live context")
+	}
+}
+
+func TestContextToWire_Canceled(t *testing.T) {
+	ctx, cancel := FromRunMetadata(RunMetadata{})
+	cancel()
+
+	if !ContextToWire(ctx).Canceled {
+		t.Errorf("wire.Canceled = false; want true after cancel()")
+	}
+}
+
+func TestRegisterCancelRelease(t *testing.T) {
+	var canceled bool
+	Register(42, func() { canceled = true })
+
+	if !Cancel(42) {
+		t.Fatalf("Cancel(42) = false; want true for a registered callID")
+	}
+	if !canceled {
+		t.Errorf("Cancel(42) did not invoke the registered CancelFunc")
+	}
+	if Cancel(42) {
+		t.Errorf("Cancel(42) = true on second call; want false, callID should be forgotten after Cancel")
+	}
+}
+
+func TestRegisterRelease(t *testing.T) {
+	var canceled bool
+	Register(7, func() { canceled = true })
+	Release(7)
+
+	if Cancel(7) {
+		t.Errorf("Cancel(7) = true after Release; want false")
+	}
+	if canceled {
+		t.Errorf("Release invoked the CancelFunc; it should only forget the registration")
+	}
+}
+
+func TestRegisterZeroCallIDIsNoop(t *testing.T) {
+	Register(0, func() { t.Error("CancelFunc for callID 0 must never be invoked") })
+	if Cancel(0) {
+		t.Errorf("Cancel(0) = true; want false, callID 0 must never be registered")
+	}
+}
+
+func TestExtractTraceContext_PrefersSpanContext(t *testing.T) {
+	ctx := WithSpanContext(WithTraceHeaders(context.Background(), "00-aaaa-bbbb-01", "vendor=x"),
+		SpanContext{TraceID: "1111111111111111", SpanID: "2222222222222222"})
+
+	traceparent, tracestate := ExtractTraceContext(ctx)
+	if want := "00-1111111111111111-2222222222222222-01"; traceparent != want {
+		t.Errorf("traceparent = %q; want %q", traceparent, want)
+	}
+	if tracestate != "" {
+		t.Errorf("tracestate = %q; want empty - SpanContext carries no tracestate", tracestate)
+	}
+}
+
+func TestExtractTraceContext_ForwardsRawHeaders(t *testing.T) {
+	ctx := WithTraceHeaders(context.Background(), "00-aaaa-bbbb-01", "vendor=x")
+
+	traceparent, tracestate := ExtractTraceContext(ctx)
+	if traceparent != "00-aaaa-bbbb-01" {
+		t.Errorf("traceparent = %q; want %q", traceparent, "00-aaaa-bbbb-01")
+	}
+	if tracestate != "vendor=x" {
+		t.Errorf("tracestate = %q; want %q", tracestate, "vendor=x")
+	}
+}
+
+func TestExtractTraceContext_SynthesizesWhenAbsent(t *testing.T) {
+	traceparent, tracestate := ExtractTraceContext(context.Background())
+
+	if matched, _ := regexp.MatchString(`^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, traceparent); !matched {
+		t.Errorf("synthesized traceparent %q does not match the W3C format", traceparent)
+	}
+	if tracestate != "" {
+		t.Errorf("tracestate = %q; want empty for a synthesized traceparent", tracestate)
+	}
+}
+
+func TestFromRunMetadata_PropagatesTraceHeaders(t *testing.T) {
+	ctx, cancel := FromRunMetadata(RunMetadata{TraceParent: "00-aaaa-bbbb-01", TraceState: "vendor=x"})
+	defer cancel()
+
+	traceparent, tracestate := ExtractTraceContext(ctx)
+	if traceparent != "00-aaaa-bbbb-01" || tracestate != "vendor=x" {
+		t.Errorf("ExtractTraceContext = %q, %q; want the headers from RunMetadata forwarded unchanged", traceparent, tracestate)
+	}
+}
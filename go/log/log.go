@@ -7,6 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/whiskeyjimbo/reglet/sdk/internal/abi"
@@ -15,6 +18,7 @@ import (
 
 // Define the host function signature for logging messages.
 // This matches the signature defined in internal/wasm/hostfuncs/registry.go.
+//
 //go:wasmimport reglet_host log_message
 func host_log_message(messagePacked uint64)
 
@@ -27,6 +31,99 @@ type LogMessageWire struct {
 	Attrs     []LogAttrWire         `json:"attrs,omitempty"`
 }
 
+// LogBatchWire is the JSON wire format for a batch of log messages shipped
+// to the host in a single host_log_message call, amortizing the ABI
+// marshal+crossing cost across up to maxBatchSize records.
+type LogBatchWire struct {
+	Records []LogMessageWire `json:"records"`
+}
+
+const (
+	// maxBatchSize is the number of buffered records that forces an
+	// immediate flush.
+	maxBatchSize = 50
+	// maxBatchAge is how long the oldest buffered record is allowed to sit
+	// before Handle forces a flush on the next call.
+	maxBatchAge = 2 * time.Second
+)
+
+// logLevelEnvVar names the environment variable a plugin's minimum log
+// level is read from (DEBUG, INFO, WARN, or ERROR). Unset or unrecognized
+// values fall back to INFO.
+const logLevelEnvVar = "REGLET_LOG_LEVEL"
+
+// minLevel is the guest-wide minimum level WasmLogHandler.Enabled allows
+// through, read once from logLevelEnvVar at package init.
+var minLevel = levelFromEnv()
+
+func levelFromEnv() slog.Level {
+	switch strings.ToUpper(os.Getenv(logLevelEnvVar)) {
+	case "DEBUG":
+		return slog.LevelDebug
+	case "WARN", "WARNING":
+		return slog.LevelWarn
+	case "ERROR":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// batch is the shared, process-wide buffer every WasmLogHandler value
+// (including those returned by WithAttrs/WithGroup) appends to and flushes
+// through. Sharing one buffer across derived handlers is what makes
+// batching worthwhile: a request-scoped logger built via WithAttrs doesn't
+// get its own host round trip.
+type batch struct {
+	mu      sync.Mutex
+	records []LogMessageWire
+	oldest  time.Time
+}
+
+var sharedBatch = &batch{}
+
+// add appends a wire record to the batch, flushing immediately if it's now
+// full or its oldest record has aged past maxBatchAge.
+func (b *batch) add(rec LogMessageWire) {
+	b.mu.Lock()
+	if len(b.records) == 0 {
+		b.oldest = rec.Timestamp
+	}
+	b.records = append(b.records, rec)
+	full := len(b.records) >= maxBatchSize
+	aged := time.Since(b.oldest) >= maxBatchAge
+	b.mu.Unlock()
+
+	if full || aged {
+		b.flush()
+	}
+}
+
+// flush ships every buffered record to the host in one LogBatchWire call.
+func (b *batch) flush() {
+	b.mu.Lock()
+	if len(b.records) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	records := b.records
+	b.records = nil
+	b.mu.Unlock()
+
+	batchBytes, err := json.Marshal(LogBatchWire{Records: records})
+	if err != nil {
+		fmt.Printf("sdk: failed to marshal log batch for host: %v, dropped %d record(s)\n", err, len(records))
+		return
+	}
+
+	packed, err := abi.PtrFromBytes(batchBytes)
+	if err != nil {
+		fmt.Printf("sdk: failed to ship log batch to host: %v, dropped %d record(s)\n", err, len(records))
+		return
+	}
+	host_log_message(packed)
+}
+
 // LogAttrWire represents a single slog attribute for wire transfer.
 type LogAttrWire struct {
 	Key   string `json:"key"`
@@ -35,13 +132,19 @@ type LogAttrWire struct {
 }
 
 // WasmLogHandler implements slog.Handler to route logs through a host function.
-type WasmLogHandler struct{}
+type WasmLogHandler struct {
+	// attrs accumulates attributes attached via WithAttrs, applied to every
+	// record this handler (or one derived from it via WithGroup) handles.
+	attrs []slog.Attr
+	// groups accumulates group names attached via WithGroup, used to qualify
+	// attribute keys (e.g. "request.method" for group "request", key "method").
+	groups []string
+}
 
-// Enabled reports whether the handler handles records at the given level.
+// Enabled reports whether the handler handles records at the given level,
+// filtering against minLevel (see logLevelEnvVar).
 func (h *WasmLogHandler) Enabled(_ context.Context, level slog.Level) bool {
-	// For now, enable all levels from plugin to host.
-	// Host can filter based on its own config.
-	return true
+	return level >= minLevel
 }
 
 // Handle serializes a slog.Record and sends it to the host via a host function.
@@ -79,33 +182,66 @@ func (h *WasmLogHandler) Handle(ctx context.Context, record slog.Record) error {
 	// But sdk/log/log.go's init() sets the default logger.
 	// So if both are imported by main, both inits run.
 
-	// Convert slog.Attr to LogAttrWire
+	// Carried attributes (from WithAttrs) come first, then the record's own.
+	for _, attr := range h.attrs {
+		logMsg.Attrs = append(logMsg.Attrs, toLogAttrWire(h.qualify(attr)))
+	}
 	record.Attrs(func(attr slog.Attr) bool {
-		logMsg.Attrs = append(logMsg.Attrs, toLogAttrWire(attr))
+		logMsg.Attrs = append(logMsg.Attrs, toLogAttrWire(h.qualify(attr)))
 		return true // Continue iterating
 	})
 
-	requestBytes, err := json.Marshal(logMsg)
-	if err != nil {
-		// Fallback to println if marshalling fails.
-		// We cannot use slog here directly as it would loop.
-		fmt.Printf("sdk: failed to marshal log message for host: %v, original: %s\n", err, record.Message)
-		return err
+	sharedBatch.add(logMsg)
+
+	// Error level bypasses batching: it's synchronous, so an error log
+	// survives even if the guest aborts before the next Flush.
+	if record.Level >= slog.LevelError {
+		sharedBatch.flush()
 	}
 
-	// Call the host function (no return value)
-	host_log_message(abi.PtrFromBytes(requestBytes))
 	return nil
 }
 
-// WithAttrs returns a new WasmLogHandler that includes the given attributes.
+// Flush ships every record currently buffered to the host immediately,
+// regardless of batch size or age. The typed and legacy method wrappers
+// call this after a handler returns (and from panic recovery) so nothing
+// buffered during the invocation is lost at the call boundary.
+func (h *WasmLogHandler) Flush() {
+	sharedBatch.flush()
+}
+
+// WithAttrs returns a new WasmLogHandler that includes the given attributes
+// on top of any this handler already carries.
 func (h *WasmLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return h // Simplified for now
+	if len(attrs) == 0 {
+		return h
+	}
+	merged := make([]slog.Attr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(merged, h.attrs)
+	merged = append(merged, attrs...)
+	return &WasmLogHandler{attrs: merged, groups: h.groups}
 }
 
-// WithGroup returns a new WasmLogHandler with the given group name.
+// WithGroup returns a new WasmLogHandler with the given group name pushed
+// onto this handler's group stack; subsequent attributes are key-qualified
+// with the full group path (see qualify).
 func (h *WasmLogHandler) WithGroup(name string) slog.Handler {
-	return h // Simplified for now
+	if name == "" {
+		return h
+	}
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	groups = append(groups, name)
+	return &WasmLogHandler{attrs: h.attrs, groups: groups}
+}
+
+// qualify prefixes attr's key with this handler's accumulated group path, so
+// "method" under WithGroup("request") becomes "request.method".
+func (h *WasmLogHandler) qualify(attr slog.Attr) slog.Attr {
+	if len(h.groups) == 0 {
+		return attr
+	}
+	return slog.Attr{Key: strings.Join(h.groups, ".") + "." + attr.Key, Value: attr.Value}
 }
 
 // toLogAttrWire converts a slog.Attr to LogAttrWire.
@@ -8,44 +8,74 @@ import (
 	"fmt"
 	"log/slog"
 	stdnet "net"
+	"sort"
 	"time"
 
+	"github.com/whiskeyjimbo/reglet/sdk"
 	"github.com/whiskeyjimbo/reglet/sdk/internal/abi"
 )
 
 // Define the host function signature for DNS lookups.
 // This matches the signature defined in internal/wasm/hostfuncs/registry.go.
+//
 //go:wasmimport reglet_host dns_lookup
 func host_dns_lookup(requestPacked uint64) uint64
 
 // WasmResolver implements net.Resolver functionality for the WASM environment.
-type WasmResolver struct{
+type WasmResolver struct {
 	// Nameserver is the address of the nameserver to use for resolution (e.g. "8.8.8.8:53").
 	// If empty, the host's default resolver is used.
 	Nameserver string
+
+	// Protocol selects how the host reaches Nameserver (or DoHEndpoint):
+	// "udp" and "tcp" are classic port-53 resolution, "dot" is DNS-over-TLS
+	// against Nameserver, and "doh" is DNS-over-HTTPS against DoHEndpoint.
+	// Empty defaults to "udp", matching the resolver's historical behavior.
+	Protocol string
+
+	// DoHEndpoint is the RFC 8484 query URL to use when Protocol is "doh",
+	// e.g. "https://cloudflare-dns.com/dns-query". Ignored otherwise.
+	DoHEndpoint string
+}
+
+// NewDoTResolver returns a WasmResolver that resolves over DNS-over-TLS
+// against host:port (e.g. NewDoTResolver("1.1.1.1", 853)).
+func NewDoTResolver(host string, port int) *WasmResolver {
+	return &WasmResolver{
+		Protocol:   "dot",
+		Nameserver: fmt.Sprintf("%s:%d", host, port),
+	}
+}
+
+// NewDoHResolver returns a WasmResolver that resolves over DNS-over-HTTPS
+// against the given RFC 8484 endpoint URL, e.g.
+// NewDoHResolver("https://cloudflare-dns.com/dns-query").
+func NewDoHResolver(url string) *WasmResolver {
+	return &WasmResolver{
+		Protocol:    "doh",
+		DoHEndpoint: url,
+	}
 }
 
 // LookupHost resolves IP addresses for a given host using the host function.
+// Matching the stdlib's own partial-success behavior, a failure on the AAAA
+// side doesn't discard a successful A lookup (and vice versa) - only an
+// error on both sides fails the whole call, so an IPv6-only resolver outage
+// doesn't break hosts that are reachable over IPv4.
 func (r *WasmResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
-	// Try A records
 	addrsA, errA := r.lookup(ctx, host, "A")
+	addrsAAAA, errAAAA := r.lookup(ctx, host, "AAAA")
+
+	if errA != nil && errAAAA != nil {
+		return nil, fmt.Errorf("sdk: A lookup: %w; AAAA lookup: %s", errA, errAAAA)
+	}
 	if errA != nil {
-		return nil, errA
+		slog.WarnContext(ctx, "sdk: A lookup failed, returning AAAA records only", "host", host, "error", errA)
+		return addrsAAAA, nil
 	}
-
-	// Try AAAA records
-	// We don't fail if AAAA fails, unless A also failed (but A returned nil error above).
-	// Actually, standard LookupHost behavior is to return what it finds.
-	// If A lookup succeeded (even with 0 records), we try AAAA.
-	addrsAAAA, errAAAA := r.lookup(ctx, host, "AAAA")
 	if errAAAA != nil {
-		// Use slog to log the error but don't fail the whole lookup if A succeeded?
-		// Standard behavior: if one fails, it might be a network issue.
-		// But typically if A succeeds, we return those.
-		// Let's be safe: return error if AAAA fails?
-		// If the host doesn't have AAAA, lookup should return empty list, not error.
-		// So real error means DNS failure.
-		return nil, errAAAA
+		slog.WarnContext(ctx, "sdk: AAAA lookup failed, returning A records only", "host", host, "error", errAAAA)
+		return addrsA, nil
 	}
 
 	return append(addrsA, addrsAAAA...), nil
@@ -76,10 +106,12 @@ func (r *WasmResolver) LookupIPAddr(ctx context.Context, host string) ([]stdnet.
 func (r *WasmResolver) lookup(ctx context.Context, hostname, recordType string) ([]string, error) {
 	wireCtx := createContextWireFormat(ctx)
 	request := DNSRequestWire{
-		Context:    wireCtx,
-		Hostname:   hostname,
-		Type:       recordType,
-		Nameserver: r.Nameserver,
+		Context:     wireCtx,
+		Hostname:    hostname,
+		Type:        recordType,
+		Nameserver:  r.Nameserver,
+		Protocol:    r.Protocol,
+		DoHEndpoint: r.DoHEndpoint,
 	}
 
 	requestBytes, err := json.Marshal(request)
@@ -87,12 +119,12 @@ func (r *WasmResolver) lookup(ctx context.Context, hostname, recordType string)
 		return nil, fmt.Errorf("sdk: failed to marshal DNS request: %w", err)
 	}
 
-	// Call the host function
-	responsePacked := host_dns_lookup(abi.PtrFromBytes(requestBytes))
-
-	// Read and unmarshal the response
-	responseBytes := abi.BytesFromPtr(responsePacked)
-	abi.DeallocatePacked(responsePacked) // Free memory on Guest side (allocated by Host for result)
+	// Call the host function, recovering from any panic during the round
+	// trip instead of aborting the module.
+	responseBytes, err := abi.Call("host_dns_lookup", requestBytes, host_dns_lookup)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: %w", err)
+	}
 
 	var response DNSResponseWire
 	if err := json.Unmarshal(responseBytes, &response); err != nil {
@@ -116,16 +148,16 @@ func init() {
 		// We implement LookupIPAddr directly to handle A/AAAA lookups through hostfuncs.
 		// For other lookup types (MX, TXT, etc.), plugin authors will need to call specific
 		// SDK functions (e.g., sdknet.LookupMX) if we don't implement them here directly.
-		
+
 		// NOTE: 'LookupIPAddr' is a method, not a field we can set on the struct literal.
 		// net.Resolver struct only has PreferGo (bool) and Dial (func).
 		// To customize LookupIPAddr behavior, we rely on PreferGo=true and the Dial function intercepting network traffic.
 		// BUT, since we cannot easily intercept the DNS protocol parsing inside net.Resolver via Dial without a full DNS server stub,
 		// we are removing the attempt to patch LookupIPAddr here.
-		
+
 		// Plugins MUST use the sdk/net package directly for lookups if they want WASM host function support.
 		// Standard net.LookupHost will likely fail or try to dial on prohibited ports.
-		
+
 		Dial: func(ctx context.Context, network, address string) (stdnet.Conn, error) {
 			slog.WarnContext(ctx, "sdk: net.DefaultResolver.Dial called, not implemented via hostfunc", "network", network, "address", address)
 			return (&stdnet.Dialer{Timeout: 5 * time.Second}).DialContext(ctx, network, address)
@@ -161,6 +193,82 @@ func (r *WasmResolver) LookupNS(ctx context.Context, host string) ([]string, err
 	return r.lookup(ctx, host, "NS")
 }
 
+// LookupSRV resolves the SRV records for a service, mirroring the stdlib's
+// net.LookupSRV. If service and proto are non-empty, the lookup name is
+// "_service._proto.name"; otherwise name is treated as the already-qualified
+// query name. The returned records are sorted by priority then weight, same
+// as net.LookupSRV.
+func (r *WasmResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*stdnet.SRV, error) {
+	target := name
+	if service != "" || proto != "" {
+		target = fmt.Sprintf("_%s._%s.%s", service, proto, name)
+	}
+
+	records, err := r.lookupSRV(ctx, target)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Priority != records[j].Priority {
+			return records[i].Priority < records[j].Priority
+		}
+		return records[i].Weight < records[j].Weight
+	})
+	return target, records, nil
+}
+
+// LookupAddr performs a reverse DNS lookup for addr, returning the PTR
+// records as hostnames. The host is responsible for translating addr into
+// the appropriate in-addr.arpa or ip6.arpa query.
+func (r *WasmResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return r.lookup(ctx, addr, "PTR")
+}
+
+// lookupSRV performs the SRV query and parses the host's structured
+// SRVRecords into stdlib *net.SRV values.
+func (r *WasmResolver) lookupSRV(ctx context.Context, hostname string) ([]*stdnet.SRV, error) {
+	wireCtx := createContextWireFormat(ctx)
+	request := DNSRequestWire{
+		Context:     wireCtx,
+		Hostname:    hostname,
+		Type:        "SRV",
+		Nameserver:  r.Nameserver,
+		Protocol:    r.Protocol,
+		DoHEndpoint: r.DoHEndpoint,
+	}
+
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: failed to marshal DNS request: %w", err)
+	}
+
+	responseBytes, err := abi.Call("host_dns_lookup", requestBytes, host_dns_lookup)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: %w", err)
+	}
+
+	var response DNSResponseWire
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return nil, fmt.Errorf("sdk: failed to unmarshal DNS response: %w", err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	records := make([]*stdnet.SRV, len(response.SRVRecords))
+	for i, rec := range response.SRVRecords {
+		records[i] = &stdnet.SRV{
+			Target:   rec.Target,
+			Port:     rec.Port,
+			Priority: rec.Priority,
+			Weight:   rec.Weight,
+		}
+	}
+	return records, nil
+}
+
 // Exported helper for plugins to use instead of net.LookupHost
 func LookupHost(ctx context.Context, host string) ([]string, error) {
 	r := &WasmResolver{}
@@ -189,4 +297,51 @@ func LookupTXT(ctx context.Context, host string) ([]string, error) {
 func LookupNS(ctx context.Context, host string) ([]string, error) {
 	r := &WasmResolver{}
 	return r.LookupNS(ctx, host)
-}
\ No newline at end of file
+}
+
+// LookupSRV resolves the SRV records for a service; see WasmResolver.LookupSRV.
+func LookupSRV(ctx context.Context, service, proto, name string) (string, []*stdnet.SRV, error) {
+	r := &WasmResolver{}
+	return r.LookupSRV(ctx, service, proto, name)
+}
+
+// LookupAddr performs a reverse DNS lookup for addr; see WasmResolver.LookupAddr.
+func LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r := &WasmResolver{}
+	return r.LookupAddr(ctx, addr)
+}
+
+// DNSRequestWire is the JSON wire format for a DNS lookup request from Guest to Host.
+type DNSRequestWire struct {
+	Context    ContextWireFormat `json:"context"`
+	Hostname   string            `json:"hostname"`
+	Type       string            `json:"type"`                 // "A", "AAAA", "CNAME", "MX", "TXT", "NS"
+	Nameserver string            `json:"nameserver,omitempty"` // Optional: "host:port"
+
+	// Protocol and DoHEndpoint mirror WasmResolver's fields of the same
+	// name, telling the host how to reach Nameserver/DoHEndpoint rather
+	// than assuming classic port-53 UDP/TCP resolution.
+	Protocol    string `json:"protocol,omitempty"`     // "udp", "tcp", "dot", or "doh"; empty defaults to "udp"
+	DoHEndpoint string `json:"doh_endpoint,omitempty"` // RFC 8484 query URL, set when Protocol is "doh"
+}
+
+// DNSResponseWire is the JSON wire format for a DNS lookup response from Host to Guest.
+type DNSResponseWire struct {
+	Records []string         `json:"records,omitempty"`
+	Error   *sdk.ErrorDetail `json:"error,omitempty"` // Structured error
+
+	// SRVRecords carries structured SRV data for a Type: "SRV" request.
+	// Unlike A/AAAA/CNAME/MX/TXT/NS/PTR, an SRV record isn't representable
+	// as a single string without losing its priority/weight/port fields, so
+	// it gets its own typed slice rather than being packed into Records.
+	SRVRecords []SRVRecordWire `json:"srv_records,omitempty"`
+}
+
+// SRVRecordWire is the JSON wire format for a single SRV record, mirroring
+// the fields of the stdlib's net.SRV.
+type SRVRecordWire struct {
+	Priority uint16 `json:"priority"`
+	Weight   uint16 `json:"weight"`
+	Port     uint16 `json:"port"`
+	Target   string `json:"target"`
+}
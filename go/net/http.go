@@ -10,6 +10,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sync"
 
 	"github.com/whiskeyjimbo/reglet/sdk" // For sdk.ErrorDetail
 	"github.com/whiskeyjimbo/reglet/sdk/internal/abi"
@@ -17,15 +18,148 @@ import (
 
 // Define the host function signature for HTTP requests.
 // This matches the signature defined in internal/wasm/hostfuncs/registry.go.
+//
 //go:wasmimport reglet_host http_request
 func host_http_request(requestPacked uint64) uint64
 
+// host_http_request_open begins a streamed request/response exchange: it
+// sends request (method, URL, headers - no inline Body) and returns a
+// packed HTTPResponseWire carrying only StreamHandle, ready for the guest
+// to write the request body (if any) and then read the response.
+//
+//go:wasmimport reglet_host http_request_open
+func host_http_request_open(requestPacked uint64) uint64
+
+// host_http_stream_write sends one frame of a streamed request body on
+// handle and returns the number of bytes the host accepted, which the
+// guest compares against the frame it sent to detect a short write. A
+// zero-length frame signals end of the request body.
+//
+//go:wasmimport reglet_host http_stream_write
+func host_http_stream_write(handle uint64, framePacked uint64) uint64
+
+// host_http_stream_read pulls the next frame of a streamed response into
+// the window at windowPtr, packed per abi.PackStreamHeader. The first frame
+// (sequence 0) is always the JSON-encoded HTTPResponseWire header envelope
+// (StatusCode, Headers, Error); frames after that are raw response-body
+// bytes, terminated by a zero-length frame.
+//
+//go:wasmimport reglet_host http_stream_read
+func host_http_stream_read(handle uint64, windowPtr uint32) uint64
+
+// host_http_stream_close releases any host-side resources associated with
+// handle. The guest calls this once a streamed response is fully drained,
+// or early to cancel a response body it stops reading partway through.
+//
+//go:wasmimport reglet_host http_stream_close
+func host_http_stream_close(handle uint64)
+
+// httpStreamFrameSize is the size of each frame RoundTrip writes to, or
+// reads from, a streamed request/response body.
+const httpStreamFrameSize = 64 * 1024
+
+// httpSingleShotThreshold is the largest request body RoundTrip will still
+// read fully into memory and send inline as base64 on a single host call.
+// Above this - or when Content-Length isn't known in advance - it streams
+// the body in httpStreamFrameSize frames instead, and the response body
+// comes back streamed the same way, so neither side is buffered whole in
+// WASM linear memory. A request with no body, or with a body at or under
+// this size, takes the single-shot path, and its whole response body is
+// decoded into memory.
+const httpSingleShotThreshold = 256 * 1024
+
 // WasmTransport implements http.RoundTripper for the WASM environment.
 // It intercepts standard library HTTP calls and routes them through the host function.
-type WasmTransport struct{}
+type WasmTransport struct {
+	// Redirects configures how 3xx responses from do are followed. A nil
+	// value uses DefaultRedirectPolicy (MaxRedirects 10, PreservePermanent
+	// false). Set MaxRedirects to 0 explicitly to disable following
+	// redirects altogether.
+	Redirects *RedirectPolicy
+
+	permanentOnce  sync.Once
+	permanentCache *permanentRedirectCache
+}
 
-// RoundTrip implements the http.RoundTripper interface.
+// RoundTrip implements the http.RoundTripper interface, following redirects
+// per t.redirectPolicy() around the single-hop do.
 func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := t.redirectPolicy()
+	originalURL := req.URL.String()
+
+	if policy.PreservePermanent {
+		if target, ok := t.permanentRedirects().get(originalURL); ok {
+			if next, err := req.URL.Parse(target); err == nil {
+				cloned := req.Clone(req.Context())
+				cloned.URL = next
+				req = cloned
+			}
+		}
+	}
+
+	var via []*http.Request
+	for {
+		resp, err := t.do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !isRedirectStatus(resp.StatusCode) || len(via) >= policy.MaxRedirects {
+			return resp, nil
+		}
+
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return resp, nil
+		}
+		target, err := req.URL.Parse(location)
+		if err != nil {
+			return resp, fmt.Errorf("sdk: invalid redirect location %q: %w", location, err)
+		}
+
+		nextReq, err := newRedirectRequest(req, target, resp.StatusCode)
+		if err != nil {
+			return resp, err
+		}
+		via = append(via, req)
+		if policy.CheckRedirect != nil {
+			if err := policy.CheckRedirect(nextReq, via); err != nil {
+				return resp, err
+			}
+		}
+		resp.Body.Close()
+
+		if policy.PreservePermanent && (resp.StatusCode == http.StatusMovedPermanently || resp.StatusCode == http.StatusPermanentRedirect) {
+			t.permanentRedirects().add(originalURL, target.String())
+		}
+		req = nextReq
+	}
+}
+
+// redirectPolicy returns t.Redirects, or DefaultRedirectPolicy if unset.
+func (t *WasmTransport) redirectPolicy() RedirectPolicy {
+	if t.Redirects != nil {
+		return *t.Redirects
+	}
+	return DefaultRedirectPolicy()
+}
+
+// permanentRedirects lazily creates t's bounded permanent-redirect cache.
+func (t *WasmTransport) permanentRedirects() *permanentRedirectCache {
+	t.permanentOnce.Do(func() {
+		t.permanentCache = newPermanentRedirectCache(permanentRedirectCacheSize)
+	})
+	return t.permanentCache
+}
+
+// do performs a single request/response round trip with no redirect
+// handling of its own - the host function is asked for exactly req's URL,
+// nothing more. RoundTrip calls this once per hop.
+func (t *WasmTransport) do(req *http.Request) (*http.Response, error) {
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody && (req.ContentLength < 0 || req.ContentLength > httpSingleShotThreshold) {
+		return t.roundTripStreaming(req)
+	}
+
 	// Create ContextWireFormat from req.Context()
 	wireCtx := createContextWireFormat(req.Context())
 
@@ -34,11 +168,11 @@ func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		Context: wireCtx,
 		Method:  req.Method,
 		URL:     req.URL.String(),
-		Headers: req.Header,
+		Headers: withTraceHeaders(req.Header, wireCtx.TraceParent, wireCtx.TraceState),
 	}
 
 	// Read request body, encode if present
-	if req.Body != nil && req.Body != http.NoBody {
+	if hasBody {
 		bodyBytes, err := io.ReadAll(req.Body)
 		if err != nil {
 			return nil, fmt.Errorf("sdk: failed to read request body: %w", err)
@@ -51,12 +185,12 @@ func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, fmt.Errorf("sdk: failed to marshal HTTP request: %w", err)
 	}
 
-	// Call the host function
-	responsePacked := host_http_request(abi.PtrFromBytes(requestBytes))
-
-	// Read and unmarshal the response
-	responseBytes := abi.BytesFromPtr(responsePacked)
-	abi.DeallocatePacked(responsePacked) // Free memory on Guest side
+	// Call the host function, recovering from any panic during the round
+	// trip instead of aborting the module.
+	responseBytes, err := abi.Call("host_http_request", requestBytes, host_http_request)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: %w", err)
+	}
 
 	var response HTTPResponseWire
 	if err := json.Unmarshal(responseBytes, &response); err != nil {
@@ -93,6 +227,204 @@ func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// roundTripStreaming handles a request whose body is absent-but-chunked,
+// unbounded, or larger than httpSingleShotThreshold: it opens a streamed
+// exchange, writes the request body in httpStreamFrameSize frames, and
+// returns a response whose Body reads the host's reply the same way.
+func (t *WasmTransport) roundTripStreaming(req *http.Request) (*http.Response, error) {
+	wireCtx := createContextWireFormat(req.Context())
+	request := HTTPRequestWire{
+		Context: wireCtx,
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: withTraceHeaders(req.Header, wireCtx.TraceParent, wireCtx.TraceState),
+	}
+
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: failed to marshal HTTP request: %w", err)
+	}
+
+	openBytes, err := abi.Call("host_http_request_open", requestBytes, host_http_request_open)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: %w", err)
+	}
+
+	var opened HTTPResponseWire
+	if err := json.Unmarshal(openBytes, &opened); err != nil {
+		return nil, fmt.Errorf("sdk: failed to unmarshal HTTP stream open response: %w", err)
+	}
+	if opened.Error != nil {
+		return nil, opened.Error
+	}
+	handle := opened.StreamHandle
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if err := writeStreamedBody(handle, req.Body); err != nil {
+			host_http_stream_close(handle)
+			return nil, err
+		}
+	}
+
+	window, err := abi.NewStreamWindow(httpStreamFrameSize)
+	if err != nil {
+		host_http_stream_close(handle)
+		return nil, fmt.Errorf("sdk: failed to allocate HTTP stream window: %w", err)
+	}
+	header, err := readStreamedHeader(handle, window)
+	if err != nil {
+		window.Close()
+		host_http_stream_close(handle)
+		return nil, err
+	}
+	if header.Error != nil {
+		window.Close()
+		host_http_stream_close(handle)
+		return nil, header.Error
+	}
+
+	resp := &http.Response{
+		StatusCode: header.StatusCode,
+		Header:     header.Headers,
+		Request:    req,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Status:     http.StatusText(header.StatusCode),
+		Body:       newStreamBody(handle, window),
+	}
+	return resp, nil
+}
+
+// withTraceHeaders returns headers with traceparent/tracestate set to
+// traceparent/tracestate, so upstream traces link with this plugin-
+// originated request, unless the caller already set traceparent
+// explicitly - a plugin author's own instrumentation takes precedence
+// over ExtractTraceContext's propagated or synthesized value. headers
+// itself is never mutated; a clone is returned whenever a header needs
+// to be added.
+func withTraceHeaders(headers http.Header, traceparent, tracestate string) http.Header {
+	if headers.Get("traceparent") != "" {
+		return headers
+	}
+	cloned := headers.Clone()
+	if cloned == nil {
+		cloned = http.Header{}
+	}
+	cloned.Set("traceparent", traceparent)
+	if tracestate != "" {
+		cloned.Set("tracestate", tracestate)
+	}
+	return cloned
+}
+
+// writeStreamedBody copies body to the host in httpStreamFrameSize frames,
+// sending a final zero-length frame to mark the end of the request body.
+func writeStreamedBody(handle uint64, body io.ReadCloser) error {
+	defer body.Close()
+
+	frame := make([]byte, httpStreamFrameSize)
+	for {
+		n, readErr := io.ReadFull(body, frame)
+		if n > 0 {
+			if err := writeStreamFrame(handle, frame[:n]); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("sdk: failed to read request body: %w", readErr)
+		}
+	}
+	return writeStreamFrame(handle, nil) // zero-length frame signals EOF
+}
+
+// writeStreamFrame packs frame and sends it on handle, failing if the host
+// didn't accept every byte.
+func writeStreamFrame(handle uint64, frame []byte) error {
+	packed, err := abi.PtrFromBytes(frame)
+	if err != nil {
+		return fmt.Errorf("sdk: failed to pack request body frame: %w", err)
+	}
+	accepted := host_http_stream_write(handle, packed)
+	abi.DeallocatePacked(packed)
+	if accepted != uint64(len(frame)) {
+		return fmt.Errorf("sdk: host accepted %d of %d request body bytes", accepted, len(frame))
+	}
+	return nil
+}
+
+// readStreamedHeader reads the first frame of a streamed response - always
+// the JSON-encoded HTTPResponseWire header envelope - out of window.
+func readStreamedHeader(handle uint64, window *abi.StreamWindow) (HTTPResponseWire, error) {
+	chunk, _, done, err := window.Next(func(windowPtr uint32) uint64 {
+		return host_http_stream_read(handle, windowPtr)
+	})
+	if err != nil {
+		return HTTPResponseWire{}, fmt.Errorf("sdk: failed to read HTTP response header: %w", err)
+	}
+	if done {
+		return HTTPResponseWire{}, fmt.Errorf("sdk: host closed HTTP stream before sending a response header")
+	}
+	var header HTTPResponseWire
+	if err := json.Unmarshal(chunk, &header); err != nil {
+		return HTTPResponseWire{}, fmt.Errorf("sdk: failed to unmarshal HTTP response header: %w", err)
+	}
+	return header, nil
+}
+
+// streamBody is an io.ReadCloser backed by host_http_stream_read calls that
+// pull one frame at a time: each Read blocks on the host producing the next
+// frame (the wasmimport call is itself synchronous, so no extra signalling
+// is needed for backpressure), and Close releases the handle, cancelling
+// the stream host-side if it wasn't already fully drained.
+type streamBody struct {
+	handle uint64
+	window *abi.StreamWindow
+	buf    []byte
+	done   bool
+	err    error
+}
+
+func newStreamBody(handle uint64, window *abi.StreamWindow) *streamBody {
+	return &streamBody{handle: handle, window: window}
+}
+
+func (s *streamBody) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		if s.done {
+			if s.err != nil {
+				return 0, s.err
+			}
+			return 0, io.EOF
+		}
+		chunk, _, done, err := s.window.Next(func(windowPtr uint32) uint64 {
+			return host_http_stream_read(s.handle, windowPtr)
+		})
+		if err != nil {
+			s.done = true
+			s.err = fmt.Errorf("sdk: failed to read HTTP response body: %w", err)
+			continue
+		}
+		if done {
+			s.done = true
+			continue
+		}
+		s.buf = chunk
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+func (s *streamBody) Close() error {
+	host_http_stream_close(s.handle)
+	s.window.Close()
+	return nil
+}
+
 // init configures the default HTTP transport to use our WasmTransport.
 // This ensures that http.Get(), http.Post(), and other functions that use
 // the default transport will use our WASM-aware implementation.
@@ -103,17 +435,30 @@ func init() {
 
 // HTTPRequestWire is the JSON wire format for an HTTP request from Guest to Host.
 type HTTPRequestWire struct {
-	Context ContextWireFormat `json:"context"`
-	Method  string            `json:"method"`
-	URL     string            `json:"url"`
+	Context ContextWireFormat   `json:"context"`
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
 	Headers map[string][]string `json:"headers,omitempty"`
-	Body    string            `json:"body,omitempty"` // Base64 encoded for binary, or plain string
+	Body    string              `json:"body,omitempty"` // Base64 encoded for binary, or plain string
+
+	// StreamHandle mirrors the field HTTPResponseWire carries for a
+	// streamed exchange. The guest never originates one on the request
+	// side today; it exists for wire-format symmetry with HTTPResponseWire
+	// should a future host implementation want to let a request reference
+	// a previously opened stream (e.g. to replay a body on redirect).
+	StreamHandle uint64 `json:"stream_handle,omitempty"`
 }
 
 // HTTPResponseWire is the JSON wire format for an HTTP response from Host to Guest.
 type HTTPResponseWire struct {
-	StatusCode int               `json:"status_code"`
+	StatusCode int                 `json:"status_code"`
 	Headers    map[string][]string `json:"headers,omitempty"`
-	Body       string            `json:"body,omitempty"` // Base64 encoded for binary, or plain string
-	Error      *sdk.ErrorDetail  `json:"error,omitempty"` // Structured error
+	Body       string              `json:"body,omitempty"`  // Base64 encoded for binary, or plain string
+	Error      *sdk.ErrorDetail    `json:"error,omitempty"` // Structured error
+
+	// StreamHandle identifies a streamed response body: http_request_open
+	// returns it alongside a zero StatusCode, and the header envelope read
+	// back via http_stream_read populates StatusCode/Headers/Error while
+	// leaving StreamHandle unset (the guest already has it).
+	StreamHandle uint64 `json:"stream_handle,omitempty"`
 }
@@ -0,0 +1,152 @@
+//go:build wasip1
+
+package net
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// RedirectPolicy configures how WasmTransport follows 3xx responses.
+type RedirectPolicy struct {
+	// MaxRedirects is the number of redirect hops RoundTrip will follow
+	// before giving up and returning the last 3xx response as-is.
+	MaxRedirects int
+
+	// PreservePermanent caches the target of a 301 or 308 response, keyed
+	// on the original request URL, so a later RoundTrip to the same URL
+	// skips straight to the cached target instead of re-running the first
+	// hop - matching the "permanent move" semantics of the status code.
+	PreservePermanent bool
+
+	// CheckRedirect, if non-nil, is called with the request about to be
+	// issued for the next hop and the requests already followed (oldest
+	// first). Returning an error stops following redirects; RoundTrip then
+	// returns that error together with the 3xx response.
+	CheckRedirect func(req *http.Request, via []*http.Request) error
+}
+
+// defaultMaxRedirects matches http.Client's default redirect limit.
+const defaultMaxRedirects = 10
+
+// permanentRedirectCacheSize bounds the PreservePermanent cache.
+const permanentRedirectCacheSize = 128
+
+// DefaultRedirectPolicy is the policy a WasmTransport with a nil Redirects
+// field behaves as: follow up to defaultMaxRedirects hops, no permanent
+// redirect caching, no CheckRedirect hook.
+func DefaultRedirectPolicy() RedirectPolicy {
+	return RedirectPolicy{MaxRedirects: defaultMaxRedirects}
+}
+
+// isRedirectStatus reports whether code is one of the HTTP redirect status
+// codes RoundTrip knows how to follow.
+func isRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// newRedirectRequest builds the request for the next hop after a 3xx
+// response to prev, pointed at target. 301/302/303 responses switch a
+// non-GET/HEAD method to GET and drop the body, mirroring http.Client's
+// historical (if technically non-compliant) behavior; 307/308 preserve the
+// method and, if prev.GetBody is set, replay the body.
+func newRedirectRequest(prev *http.Request, target *url.URL, statusCode int) (*http.Request, error) {
+	method := prev.Method
+	preserveBody := statusCode == http.StatusTemporaryRedirect || statusCode == http.StatusPermanentRedirect
+	if !preserveBody && method != http.MethodGet && method != http.MethodHead {
+		method = http.MethodGet
+	}
+
+	next, err := http.NewRequestWithContext(prev.Context(), method, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: failed to build redirect request: %w", err)
+	}
+	next.Header = prev.Header.Clone()
+
+	hasBody := prev.Body != nil && prev.Body != http.NoBody
+	if preserveBody && hasBody {
+		if prev.GetBody == nil {
+			return nil, fmt.Errorf("sdk: cannot replay request body for %d redirect: original request has no GetBody", statusCode)
+		}
+		body, err := prev.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("sdk: failed to replay request body for redirect: %w", err)
+		}
+		next.Body = body
+		next.ContentLength = prev.ContentLength
+		next.GetBody = prev.GetBody
+	} else if !preserveBody {
+		next.Header.Del("Content-Length")
+		next.Header.Del("Content-Type")
+	}
+
+	return next, nil
+}
+
+// permanentRedirectCache is a bounded least-recently-used cache mapping an
+// original request URL to the target a 301/308 response pointed it at.
+type permanentRedirectCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// permanentRedirectEntry is the value stored in permanentRedirectCache.order.
+type permanentRedirectEntry struct {
+	key    string
+	target string
+}
+
+func newPermanentRedirectCache(capacity int) *permanentRedirectCache {
+	return &permanentRedirectCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached target for key and marks it most recently used.
+func (c *permanentRedirectCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*permanentRedirectEntry).target, true
+}
+
+// add records key -> target, evicting the least recently used entry if the
+// cache is over capacity.
+func (c *permanentRedirectCache) add(key, target string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*permanentRedirectEntry).target = target
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&permanentRedirectEntry{key: key, target: target})
+	c.entries[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*permanentRedirectEntry).key)
+		}
+	}
+}
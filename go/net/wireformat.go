@@ -8,6 +8,12 @@ import (
 	sdkcontext "github.com/whiskeyjimbo/reglet/sdk/internal/context"
 )
 
+// ContextWireFormat is an alias for sdkcontext's wire format, so
+// DNSRequestWire/HTTPRequestWire's Context field and sdkcontext.ContextToWire
+// share one definition instead of two structurally-identical types drifting
+// apart.
+type ContextWireFormat = sdkcontext.ContextWireFormat
+
 // createContextWireFormat extracts relevant info from a Go context into the wire format.
 // This is now a wrapper around sdkcontext.ContextToWire for backwards compatibility.
 func createContextWireFormat(ctx context.Context) ContextWireFormat {
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime/debug" // For stack traces in panic recovery
+	"sync"
 
 	"github.com/whiskeyjimbo/reglet/sdk/internal/abi"
+	sdkcontext "github.com/whiskeyjimbo/reglet/sdk/internal/context"
 )
 
 // Plugin is the interface every Reglet plugin must implement.
@@ -22,6 +24,21 @@ type Plugin interface {
 	Check(ctx context.Context, config Config) (Evidence, error)
 }
 
+// StreamingPlugin is implemented by plugins whose Check emits progressive
+// results instead of one final Evidence - tailing logs, watching
+// Kubernetes pod state, or any check whose signal arrives over time. A
+// Plugin that also implements StreamingPlugin gets a second export,
+// _observe_stream, alongside _observe; hosts that don't care about
+// streaming keep calling _observe as always.
+type StreamingPlugin interface {
+	Plugin
+	// CheckStream runs like Check, but sends each Evidence to results as it
+	// becomes available instead of returning a single value. CheckStream
+	// does not need to close results - the SDK closes it once CheckStream
+	// returns (or panics).
+	CheckStream(ctx context.Context, config Config, results chan<- Evidence) error
+}
+
 // Internal variable to hold the user's plugin implementation.
 var userPlugin Plugin
 
@@ -33,20 +50,48 @@ func Register(p Plugin) {
 		return
 	}
 	userPlugin = p
+	abi.NegotiateCodecs()
 	slog.Info("sdk: plugin registered successfully", "userPlugin_addr", fmt.Sprintf("%p", &userPlugin))
 }
 
+// newCallContext decodes the RunMetadata the host packed into
+// metaPtr/metaLen into a context.Context for _describe/_schema/_observe to
+// run under - a zero-length slice, or one that fails to decode, falls back
+// to an empty RunMetadata, so hosts that don't send metadata yet still
+// work, just without a deadline/trace ID/tenant or cancellation support.
+// The returned release func must be deferred by the caller: it cancels ctx,
+// releasing its resources per context.Context's contract, and deregisters
+// meta's CallID (if any) so a later _cancel can't reach a call that has
+// already finished.
+func newCallContext(metaPtr, metaLen uint32) (ctx context.Context, release func()) {
+	var meta sdkcontext.RunMetadata
+	if metaLen > 0 {
+		if metaBytes, err := abi.BytesFromPtr(abi.PackPtrLen(metaPtr, metaLen)); err == nil {
+			_ = json.Unmarshal(metaBytes, &meta)
+		}
+	}
+
+	ctx, cancel := sdkcontext.FromRunMetadata(meta)
+	sdkcontext.Register(meta.CallID, cancel)
+	return ctx, func() {
+		cancel()
+		sdkcontext.Release(meta.CallID)
+	}
+}
+
 // Define the functions that will be exported to the WASM host.
 // These functions perform panic recovery and ABI translation.
 
 //go:wasmexport describe
-func _describe() uint64 {
+func _describe(metaPtr uint32, metaLen uint32) uint64 {
+	ctx, release := newCallContext(metaPtr, metaLen)
+	defer release()
+
 	return handleExportedCall(func() (interface{}, error) {
 		if userPlugin == nil {
 			return nil, fmt.Errorf("plugin not registered")
 		}
-		// Context propagation is for a later phase, using Background for now.
-		metadata, err := userPlugin.Describe(context.Background())
+		metadata, err := userPlugin.Describe(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -58,14 +103,16 @@ func _describe() uint64 {
 }
 
 //go:wasmexport schema
-func _schema() uint64 {
+func _schema(metaPtr uint32, metaLen uint32) uint64 {
+	ctx, release := newCallContext(metaPtr, metaLen)
+	defer release()
+
 	return handleExportedCall(func() (interface{}, error) {
 		slog.Debug("sdk: _schema called", "userPlugin_addr", fmt.Sprintf("%p", &userPlugin), "userPlugin_nil", userPlugin == nil)
 		if userPlugin == nil {
 			return nil, fmt.Errorf("plugin not registered")
 		}
-		// Context propagation is for a later phase, using Background for now.
-		schemaBytes, err := userPlugin.Schema(context.Background())
+		schemaBytes, err := userPlugin.Schema(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -75,7 +122,10 @@ func _schema() uint64 {
 }
 
 //go:wasmexport observe
-func _observe(configPtr uint32, configLen uint32) uint64 {
+func _observe(metaPtr uint32, metaLen uint32, configPtr uint32, configLen uint32) uint64 {
+	ctx, release := newCallContext(metaPtr, metaLen)
+	defer release()
+
 	return handleExportedCall(func() (interface{}, error) {
 		slog.Debug("sdk: _observe called", "userPlugin_addr", fmt.Sprintf("%p", &userPlugin), "userPlugin_nil", userPlugin == nil)
 		if userPlugin == nil {
@@ -83,14 +133,16 @@ func _observe(configPtr uint32, configLen uint32) uint64 {
 		}
 
 		// Read config from WASM memory
-		configBytes := abi.BytesFromPtr(abi.PackPtrLen(configPtr, configLen))
+		configBytes, err := abi.BytesFromPtr(abi.PackPtrLen(configPtr, configLen))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
 		var config Config
 		if err := json.Unmarshal(configBytes, &config); err != nil {
 			return nil, fmt.Errorf("failed to parse config: %w", err)
 		}
 
-		// Context propagation is for a later phase, using Background for now.
-		evidence, err := userPlugin.Check(context.Background(), config)
+		evidence, err := userPlugin.Check(ctx, config)
 		if err != nil {
 			// If user's check returns an error, embed it in Evidence
 			evidence.Status = false
@@ -100,6 +152,164 @@ func _observe(configPtr uint32, configLen uint32) uint64 {
 	})
 }
 
+// _cancel lets the host signal that a running _describe/_schema/_observe
+// call should stop: callID must match the RunMetadata.CallID the host
+// packed into that call's metaPtr. Returns 1 if callID was still in
+// flight and its context was canceled, 0 if it had already finished - or
+// was never registered, e.g. the call's RunMetadata had no CallID.
+//
+//go:wasmexport cancel
+func _cancel(callID uint64) uint64 {
+	if sdkcontext.Cancel(callID) {
+		return 1
+	}
+	return 0
+}
+
+// observeStreamState is one in-flight or completed _observe_stream session,
+// addressed by the handle _observe_stream returned. results is fed by the
+// goroutine running CheckStream and drained by repeated _observe_stream_next
+// calls; closing it is what lets _observe_stream_next report end of stream.
+type observeStreamState struct {
+	results chan Evidence
+}
+
+var (
+	observeStreamsMu  sync.Mutex
+	observeStreams    = map[uint32]*observeStreamState{}
+	nextObserveStream uint32
+)
+
+func registerObserveStream(results chan Evidence) uint32 {
+	observeStreamsMu.Lock()
+	defer observeStreamsMu.Unlock()
+	nextObserveStream++
+	handle := nextObserveStream
+	observeStreams[handle] = &observeStreamState{results: results}
+	return handle
+}
+
+func observeStreamByHandle(handle uint32) (*observeStreamState, bool) {
+	observeStreamsMu.Lock()
+	defer observeStreamsMu.Unlock()
+	st, ok := observeStreams[handle]
+	return st, ok
+}
+
+func closeObserveStream(handle uint32) {
+	observeStreamsMu.Lock()
+	defer observeStreamsMu.Unlock()
+	delete(observeStreams, handle)
+}
+
+// _observe_stream is _observe's streaming counterpart: instead of running
+// Check synchronously and returning one Evidence, it starts userPlugin's
+// CheckStream in the background and returns a handle the host repeatedly
+// passes to _observe_stream_next to drain results as they arrive. Calling
+// it on a Plugin that doesn't implement StreamingPlugin, or with a config
+// that fails to decode, still returns a valid handle - its first (and
+// only) _observe_stream_next call reports the failure as an error Evidence.
+//
+//go:wasmexport observe_stream
+func _observe_stream(configPtr uint32, configLen uint32) uint64 {
+	results := make(chan Evidence, 16)
+	handle := registerObserveStream(results)
+
+	sp, ok := userPlugin.(StreamingPlugin)
+	if !ok {
+		results <- Evidence{Status: false, Error: &ErrorDetail{
+			Message: "plugin does not implement StreamingPlugin", Type: "internal",
+		}}
+		close(results)
+		return uint64(handle)
+	}
+
+	configBytes, err := abi.BytesFromPtr(abi.PackPtrLen(configPtr, configLen))
+	if err != nil {
+		results <- Evidence{Status: false, Error: ToErrorDetail(fmt.Errorf("failed to read config: %w", err))}
+		close(results)
+		return uint64(handle)
+	}
+	var config Config
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		results <- Evidence{Status: false, Error: ToErrorDetail(fmt.Errorf("failed to parse config: %w", err))}
+		close(results)
+		return uint64(handle)
+	}
+
+	go runStreamingCheck(sp, config, results)
+	return uint64(handle)
+}
+
+// runStreamingCheck drives a StreamingPlugin's CheckStream, recovering a
+// panic from producing any single Evidence (CheckStream itself, or code it
+// calls) as one final error Evidence rather than losing results already
+// sent, then always closes results so _observe_stream_next can report end
+// of stream.
+func runStreamingCheck(sp StreamingPlugin, config Config, results chan<- Evidence) {
+	defer close(results)
+	defer func() {
+		if r := recover(); r != nil {
+			abi.FreeAllTracked()
+			errDetail := &ErrorDetail{
+				Message: fmt.Sprintf("plugin panic: %v", r),
+				Type:    "panic",
+				Stack:   debug.Stack(),
+			}
+			slog.Error("sdk: streaming plugin panic recovered", "error", errDetail.Message)
+			results <- Evidence{Status: false, Error: errDetail}
+		}
+	}()
+
+	// Context propagation is for a later phase, using Background for now.
+	if err := sp.CheckStream(context.Background(), config, results); err != nil {
+		results <- Evidence{Status: false, Error: ToErrorDetail(err)}
+	}
+}
+
+// _observe_stream_next drains one Evidence from the stream handle returned
+// by _observe_stream, blocking until either the next result arrives or the
+// stream ends. A result is packed exactly like _observe's return value
+// (a fresh pointer/length pair over one JSON-marshaled Evidence); end of
+// stream - including an unknown handle - is reported as 0, mirroring
+// StreamWindow.Next's zero-length-means-EOF convention, since
+// abi.PackPtrLen never produces 0 for an actual chunk. The host must not
+// call this again for handle once it sees 0.
+//
+//go:wasmexport observe_stream_next
+func _observe_stream_next(handle uint32) (packedResult uint64) {
+	defer func() {
+		if r := recover(); r != nil {
+			abi.FreeAllTracked()
+			slog.Error("sdk: _observe_stream_next panic recovered", "error", fmt.Sprintf("%v", r))
+			packedResult = packEvidenceWithError(Evidence{Status: false, Error: &ErrorDetail{
+				Message: fmt.Sprintf("plugin panic: %v", r), Type: "panic", Stack: debug.Stack(),
+			}})
+		}
+	}()
+
+	stream, ok := observeStreamByHandle(handle)
+	if !ok {
+		return 0
+	}
+
+	ev, open := <-stream.results
+	if !open {
+		closeObserveStream(handle)
+		return 0
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return packEvidenceWithError(Evidence{Status: false, Error: ToErrorDetail(err)})
+	}
+	packed, err := abi.PtrFromBytes(data)
+	if err != nil {
+		return packEvidenceWithError(Evidence{Status: false, Error: ToErrorDetail(err)})
+	}
+	return packed
+}
+
 // handleExportedCall is a generic wrapper for WASM exported functions.
 // It provides panic recovery, error handling, and JSON serialization.
 // It ensures that on any error or panic, a structured Evidence with ErrorDetail is returned.
@@ -141,7 +351,13 @@ func handleExportedCall(f func() (interface{}, error)) (packedResult uint64) {
 		}
 	}
 
-	packedResult = abi.PtrFromBytes(dataToMarshal)
+	packed, err := abi.PtrFromBytes(dataToMarshal)
+	if err != nil {
+		slog.Error("sdk: result too large to send to host", "error", err.Error())
+		packedResult = packEvidenceWithError(Evidence{Status: false, Error: ToErrorDetail(err)})
+		return
+	}
+	packedResult = packed
 	return
 }
 
@@ -155,5 +371,12 @@ func packEvidenceWithError(ev Evidence) uint64 {
 		fallbackErr := &ErrorDetail{Message: "sdk: critical error during error marshalling", Type: "internal"}
 		data, _ = json.Marshal(Evidence{Status: false, Error: fallbackErr}) // Try to marshal a generic error
 	}
-	return abi.PtrFromBytes(data)
+	packed, packErr := abi.PtrFromBytes(data)
+	if packErr != nil {
+		// Nothing left to fall back to - log and signal an empty result
+		// rather than returning a pointer into memory we never allocated.
+		slog.Error("sdk: critical - error evidence itself exceeds the message size limit", "error", packErr.Error())
+		return 0
+	}
+	return packed
 }
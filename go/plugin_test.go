@@ -85,7 +85,7 @@ func TestEvidence_Serialization(t *testing.T) {
 			evidence: Evidence{
 				Status: true,
 				Data: map[string]interface{}{
-					"stdout": "hello world",
+					"stdout":    "hello world",
 					"exit_code": 0,
 				},
 			},
@@ -282,9 +282,7 @@ func TestConfigErrorHelper(t *testing.T) {
 	assert.False(t, evidence.Status)
 	require.NotNil(t, evidence.Error)
 	assert.Contains(t, evidence.Error.Message, "missing required field")
-	// Note: ConfigError currently uses ToErrorDetail which returns "internal" type
-	// This will be improved in Phase 4 when we add custom error types
-	assert.Equal(t, "internal", evidence.Error.Type)
+	assert.Equal(t, "config", evidence.Error.Type)
 }
 
 func TestNetworkErrorHelper(t *testing.T) {
@@ -300,3 +298,62 @@ func TestNetworkErrorHelper(t *testing.T) {
 	assert.NotNil(t, evidence.Error.Wrapped)
 	assert.Contains(t, evidence.Error.Wrapped.Message, "connection timeout")
 }
+
+func TestToErrorDetail_SentinelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantType string
+		wantCode string
+	}{
+		{"config sentinel", fmt.Errorf("missing field: %w", ErrConfig), "config", ""},
+		{"network sentinel", fmt.Errorf("dial failed: %w", ErrNetwork), "network", ""},
+		{"capability sentinel", fmt.Errorf("outbound denied: %w", ErrCapabilityDenied), "capability", ""},
+		{"timeout sentinel", fmt.Errorf("deadline exceeded: %w", ErrTimeout), "timeout", ""},
+		{"validation sentinel", fmt.Errorf("schema mismatch: %w", ErrValidation), "validation", ""},
+		{"exec sentinel", fmt.Errorf("command failed: %w", ErrExec), "exec", ""},
+		{"keyvalue sentinel", fmt.Errorf("store unavailable: %w", ErrKeyValue), "capability", "keyvalue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail := ToErrorDetail(tt.err)
+			require.NotNil(t, detail)
+			assert.Equal(t, tt.err.Error(), detail.Message)
+			assert.Equal(t, tt.wantType, detail.Type)
+			assert.Equal(t, tt.wantCode, detail.Code)
+		})
+	}
+}
+
+func TestToErrorDetail_WrapsCauseRecursively(t *testing.T) {
+	// errors.Is walks the whole chain, so a sentinel buried under a plain
+	// wrapper still classifies the outermost error - but Wrapped still
+	// preserves the intermediate layer's own message.
+	cause := fmt.Errorf("dial tcp 10.0.0.1:443: %w", ErrTimeout)
+	err := fmt.Errorf("request to api.example.com failed: %w", cause)
+
+	detail := ToErrorDetail(err)
+	require.NotNil(t, detail)
+	assert.Equal(t, "timeout", detail.Type)
+
+	require.NotNil(t, detail.Wrapped)
+	assert.Equal(t, "timeout", detail.Wrapped.Type)
+	assert.Contains(t, detail.Wrapped.Message, "dial tcp")
+}
+
+func TestToErrorDetail_TypedErrorsStillClassified(t *testing.T) {
+	confErr := &ConfigValidationError{Field: "host", Err: fmt.Errorf("required")}
+	detail := ToErrorDetail(confErr)
+	require.NotNil(t, detail)
+	assert.Equal(t, "config", detail.Type)
+	assert.Equal(t, "host", detail.Code)
+	require.NotNil(t, detail.Wrapped)
+	assert.Equal(t, "required", detail.Wrapped.Message)
+
+	netErr := &NetworkOpError{Operation: "tcp_connect", Target: "10.0.0.1:443", Err: fmt.Errorf("refused")}
+	detail = ToErrorDetail(netErr)
+	require.NotNil(t, detail)
+	assert.Equal(t, "network", detail.Type)
+	assert.Equal(t, "tcp_connect", detail.Code)
+}
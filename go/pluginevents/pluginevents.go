@@ -0,0 +1,80 @@
+//go:build wasip1
+
+// Package pluginevents publishes strongly-typed plugin lifecycle events
+// across the host boundary, modeled on Docker's plugin event stream: other
+// host-side subsystems (schedulers, audit, swarm-style controllers) can
+// subscribe to Kind-tagged Events instead of scraping logs.
+package pluginevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/whiskeyjimbo/reglet/sdk/internal/abi"
+)
+
+// Define the host function signature for publishing a plugin event. This
+// matches the signature defined in internal/wasm/hostfuncs/registry.go,
+// next to log_message.
+//
+//go:wasmimport reglet_host plugin_event
+func host_plugin_event(eventPacked uint64)
+
+// Kind identifies the phase of an observation an Event describes, or a
+// custom domain event emitted by plugin code (e.g. "cert_expiring_soon").
+type Kind string
+
+// Lifecycle kinds. Nothing in this module publishes them itself; Emit ships
+// whatever Event plugin code passes it.
+const (
+	KindRegistered       Kind = "registered"
+	KindConfigParsed     Kind = "config_parsed"
+	KindHandlerStarted   Kind = "handler_started"
+	KindHandlerFinished  Kind = "handler_finished"
+	KindCapabilityDenied Kind = "capability_denied"
+	KindPanicRecovered   Kind = "panic_recovered"
+	KindEvidenceEmitted  Kind = "evidence_emitted"
+)
+
+// ErrorDetail mirrors wireformat.ErrorDetail's wire shape for inclusion on
+// an Event.
+type ErrorDetail struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+}
+
+// Event describes one phase a plugin goes through during an observation, or
+// a custom domain event a plugin publishes via Emit.
+type Event struct {
+	Kind      Kind           `json:"kind"`
+	Timestamp time.Time      `json:"timestamp"`
+	Plugin    string         `json:"plugin"`
+	Service   string         `json:"service,omitempty"`
+	Operation string         `json:"operation,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+	Error     *ErrorDetail   `json:"error,omitempty"`
+}
+
+// Emit publishes ev to the host. Timestamp is set to time.Now() if left
+// zero.
+func Emit(ev Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+
+	eventBytes, err := json.Marshal(ev)
+	if err != nil {
+		fmt.Printf("sdk: failed to marshal plugin event for host: %v, kind: %s\n", err, ev.Kind)
+		return
+	}
+
+	packed, err := abi.PtrFromBytes(eventBytes)
+	if err != nil {
+		fmt.Printf("sdk: failed to ship plugin event to host: %v, kind: %s\n", err, ev.Kind)
+		return
+	}
+	host_plugin_event(packed)
+}
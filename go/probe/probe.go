@@ -0,0 +1,396 @@
+//go:build wasip1
+
+// Package probe implements a declarative, blackbox_exporter-style probing
+// framework on top of the SDK's net package. A ProbeSpec describes what to
+// check and how to validate the result; Run executes it and returns a
+// ProbeResult carrying per-phase timings and validation outcomes.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/whiskeyjimbo/reglet/sdk"
+	sdknet "github.com/whiskeyjimbo/reglet/sdk/net"
+)
+
+// ProbeSpec describes a single blackbox probe. Exactly one of TCPProbe,
+// DNSProbe, HTTPProbe, or ICMPProbe should be set; Run dispatches on the
+// first non-nil one, checked in the order HTTPProbe, DNSProbe, TCPProbe,
+// ICMPProbe.
+type ProbeSpec struct {
+	// Timeout bounds the whole probe, including connection setup. Zero means
+	// no additional timeout beyond whatever ctx already carries.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	TCPProbe  *TCPProbeSpec  `json:"tcp_probe,omitempty"`
+	DNSProbe  *DNSProbeSpec  `json:"dns_probe,omitempty"`
+	HTTPProbe *HTTPProbeSpec `json:"http_probe,omitempty"`
+	ICMPProbe *ICMPProbeSpec `json:"icmp_probe,omitempty"`
+}
+
+// HTTPProbeSpec configures an HTTP(S) blackbox probe.
+type HTTPProbeSpec struct {
+	URL             string            `json:"url"`
+	Method          string            `json:"method,omitempty"` // default "GET"
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	FollowRedirects bool              `json:"follow_redirects,omitempty"`
+	Validation      HTTPValidation    `json:"validation,omitempty"`
+}
+
+// HTTPValidation lists the predicates an HTTP probe response is checked
+// against. A nil/empty field is skipped rather than treated as a failure.
+type HTTPValidation struct {
+	// ValidStatusCodeRanges lists the inclusive status ranges considered
+	// healthy. Defaults to [200,299] when empty.
+	ValidStatusCodeRanges []StatusRange `json:"valid_status_code_ranges,omitempty"`
+
+	RequiredHeaders  []string `json:"required_headers,omitempty"`
+	ForbiddenHeaders []string `json:"forbidden_headers,omitempty"`
+
+	// BodyRegexMatch/BodyRegexNonMatch are compiled with regexp.Compile
+	// (RE2 syntax) and run against the response body.
+	BodyRegexMatch    string `json:"body_regex_match,omitempty"`
+	BodyRegexNonMatch string `json:"body_regex_non_match,omitempty"`
+}
+
+// StatusRange is an inclusive [Min, Max] HTTP status code range.
+type StatusRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// DNSProbeSpec configures a DNS blackbox probe.
+type DNSProbeSpec struct {
+	Hostname   string        `json:"hostname"`
+	RecordType string        `json:"record_type,omitempty"` // "A", "AAAA", "CNAME", "MX", "TXT", "NS"; default "A"
+	Nameserver string        `json:"nameserver,omitempty"`
+	Validation DNSValidation `json:"validation,omitempty"`
+}
+
+// DNSValidation lists the predicates a DNS probe response is checked
+// against.
+type DNSValidation struct {
+	// ValidateAnswerRRs is a list of regexes; every entry must match at
+	// least one returned record for the probe to pass.
+	ValidateAnswerRRs []string `json:"validate_answer_rrs,omitempty"`
+
+	// ValidateAuthorityRRs mirrors blackbox_exporter's authority-section
+	// check. DNSResponseWire doesn't carry an authority section today (the
+	// host-side resolver only reports answers), so a non-empty value here
+	// always fails validation with a clear "no authority data" detail
+	// rather than silently passing - see validateDNS.
+	ValidateAuthorityRRs []string `json:"validate_authority_rrs,omitempty"`
+}
+
+// TCPProbeSpec configures a scripted TCP send/expect probe, e.g. for
+// protocols that announce themselves in plaintext before a TLS upgrade
+// (STARTTLS-style handshakes).
+type TCPProbeSpec struct {
+	Address  string            `json:"address"`
+	TLS      bool              `json:"tls,omitempty"`
+	Dialogue []TCPDialogueStep `json:"dialogue,omitempty"`
+}
+
+// TCPDialogueStep is one step of a scripted TCP conversation: send Send (if
+// non-empty), then read until ExpectRegex matches (if non-empty).
+type TCPDialogueStep struct {
+	Send        string `json:"send,omitempty"`
+	ExpectRegex string `json:"expect_regex,omitempty"`
+}
+
+// ICMPProbeSpec configures an ICMP echo probe.
+type ICMPProbeSpec struct {
+	Host  string `json:"host"`
+	Count int    `json:"count,omitempty"`
+}
+
+// ProbeResult is the outcome of running a ProbeSpec. Run measures only
+// FirstByteMs (HTTP probes), DNSLookupMs (DNS probes) and TotalMs;
+// ConnectMs and TLSHandshakeMs are never set, and any timing the probe
+// that ran doesn't measure is left at zero.
+type ProbeResult struct {
+	Success bool             `json:"success"`
+	Error   *sdk.ErrorDetail `json:"error,omitempty"`
+
+	DNSLookupMs    float64 `json:"dns_lookup_ms,omitempty"`
+	ConnectMs      float64 `json:"connect_ms,omitempty"`
+	TLSHandshakeMs float64 `json:"tls_handshake_ms,omitempty"`
+	FirstByteMs    float64 `json:"first_byte_ms,omitempty"`
+	TotalMs        float64 `json:"total_ms"`
+
+	StatusCode int      `json:"status_code,omitempty"`
+	Records    []string `json:"records,omitempty"`
+
+	Validations []ValidationOutcome `json:"validations,omitempty"`
+}
+
+// ValidationOutcome is the result of one validation predicate from the
+// probe's spec.
+type ValidationOutcome struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Run executes spec and returns its result. Run never returns a non-nil
+// error for a probe that ran to completion, even an unhealthy one -
+// ProbeResult.Success and ProbeResult.Error carry that outcome instead, so
+// callers (and Op[ProbeSpec, ProbeResult]-style handlers) can distinguish
+// "the probe observed a failure" from "the probe itself couldn't run". An
+// error is only returned when the spec can't be dispatched at all (e.g. no
+// variant set).
+//
+// TCPProbe and ICMPProbe aren't wired to a live transport in this SDK build
+// - there's no host function for a raw TCP dial or ICMP echo in this
+// package yet (unlike HTTP and DNS, which reuse WasmTransport and
+// WasmResolver) - so both report a failed ProbeResult with a *sdk.TCPError
+// or *sdk.NetworkOpError explaining the gap rather than attempting real I/O.
+func Run(ctx context.Context, spec *ProbeSpec) (*ProbeResult, error) {
+	if spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, spec.Timeout)
+		defer cancel()
+	}
+
+	switch {
+	case spec.HTTPProbe != nil:
+		return runHTTP(ctx, spec.HTTPProbe)
+	case spec.DNSProbe != nil:
+		return runDNS(ctx, spec.DNSProbe)
+	case spec.TCPProbe != nil:
+		return runTCP(ctx, spec.TCPProbe)
+	case spec.ICMPProbe != nil:
+		return runICMP(ctx, spec.ICMPProbe)
+	}
+	return nil, fmt.Errorf("sdk: probe spec has no tcp_probe/dns_probe/http_probe/icmp_probe set")
+}
+
+func runHTTP(ctx context.Context, spec *HTTPProbeSpec) (*ProbeResult, error) {
+	start := time.Now()
+	method := spec.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if spec.Body != "" {
+		body = strings.NewReader(spec.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, spec.URL, body)
+	if err != nil {
+		return failedResult(start, &sdk.HTTPError{Method: method, URL: spec.URL, Err: err}), nil
+	}
+	for k, v := range spec.Headers {
+		req.Header.Set(k, v)
+	}
+
+	transport := &sdknet.WasmTransport{}
+	client := &http.Client{Transport: transport}
+	if !spec.FollowRedirects {
+		// WasmTransport follows redirects itself unless its policy allows
+		// zero hops.
+		transport.Redirects = &sdknet.RedirectPolicy{MaxRedirects: 0}
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return failedResult(start, &sdk.HTTPError{Method: method, URL: spec.URL, Err: err}), nil
+	}
+	defer resp.Body.Close()
+
+	firstByteMs := time.Since(start).Seconds() * 1000
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return failedResult(start, &sdk.HTTPError{Method: method, URL: spec.URL, StatusCode: resp.StatusCode, Err: err}), nil
+	}
+
+	result := &ProbeResult{
+		StatusCode:  resp.StatusCode,
+		FirstByteMs: firstByteMs,
+	}
+	result.Validations = validateHTTP(spec.Validation, resp, respBody)
+	result.Success = allPassed(result.Validations)
+	if !result.Success {
+		result.Error = sdk.ToErrorDetail(&sdk.HTTPError{Method: method, URL: spec.URL, StatusCode: resp.StatusCode, Err: fmt.Errorf("validation failed")})
+	}
+	result.TotalMs = time.Since(start).Seconds() * 1000
+	return result, nil
+}
+
+func validateHTTP(v HTTPValidation, resp *http.Response, body []byte) []ValidationOutcome {
+	var outcomes []ValidationOutcome
+
+	ranges := v.ValidStatusCodeRanges
+	if len(ranges) == 0 {
+		ranges = []StatusRange{{Min: 200, Max: 299}}
+	}
+	statusOK := false
+	for _, r := range ranges {
+		if resp.StatusCode >= r.Min && resp.StatusCode <= r.Max {
+			statusOK = true
+			break
+		}
+	}
+	outcomes = append(outcomes, ValidationOutcome{
+		Name:   "status_code",
+		Passed: statusOK,
+		Detail: fmt.Sprintf("status %d", resp.StatusCode),
+	})
+
+	for _, h := range v.RequiredHeaders {
+		_, ok := resp.Header[http.CanonicalHeaderKey(h)]
+		outcomes = append(outcomes, ValidationOutcome{Name: "required_header:" + h, Passed: ok})
+	}
+	for _, h := range v.ForbiddenHeaders {
+		_, present := resp.Header[http.CanonicalHeaderKey(h)]
+		outcomes = append(outcomes, ValidationOutcome{Name: "forbidden_header:" + h, Passed: !present})
+	}
+
+	if v.BodyRegexMatch != "" {
+		outcomes = append(outcomes, regexOutcome("body_regex_match", v.BodyRegexMatch, body, true))
+	}
+	if v.BodyRegexNonMatch != "" {
+		outcomes = append(outcomes, regexOutcome("body_regex_non_match", v.BodyRegexNonMatch, body, false))
+	}
+
+	return outcomes
+}
+
+// regexOutcome compiles pattern and reports whether it matching body agrees
+// with wantMatch.
+func regexOutcome(name, pattern string, body []byte, wantMatch bool) ValidationOutcome {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ValidationOutcome{Name: name, Passed: false, Detail: fmt.Sprintf("invalid regex: %v", err)}
+	}
+	matched := re.Match(body)
+	return ValidationOutcome{Name: name, Passed: matched == wantMatch}
+}
+
+func runDNS(ctx context.Context, spec *DNSProbeSpec) (*ProbeResult, error) {
+	start := time.Now()
+	recordType := spec.RecordType
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	resolver := &sdknet.WasmResolver{Nameserver: spec.Nameserver}
+
+	var records []string
+	var err error
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA":
+		records, err = resolver.LookupHost(ctx, spec.Hostname)
+	case "CNAME":
+		var cname string
+		cname, err = resolver.LookupCNAME(ctx, spec.Hostname)
+		if err == nil {
+			records = []string{cname}
+		}
+	case "MX":
+		records, err = resolver.LookupMX(ctx, spec.Hostname)
+	case "TXT":
+		records, err = resolver.LookupTXT(ctx, spec.Hostname)
+	case "NS":
+		records, err = resolver.LookupNS(ctx, spec.Hostname)
+	default:
+		err = fmt.Errorf("unsupported record type %q", recordType)
+	}
+
+	dnsErr := &sdk.DNSError{Hostname: spec.Hostname, RecordType: recordType, Nameserver: spec.Nameserver}
+	if err != nil {
+		dnsErr.Err = err
+		return failedResult(start, dnsErr), nil
+	}
+
+	result := &ProbeResult{
+		DNSLookupMs: time.Since(start).Seconds() * 1000,
+		Records:     records,
+	}
+	result.Validations = validateDNS(spec.Validation, records)
+	result.Success = allPassed(result.Validations)
+	if !result.Success {
+		dnsErr.Err = fmt.Errorf("validation failed")
+		result.Error = sdk.ToErrorDetail(dnsErr)
+	}
+	result.TotalMs = time.Since(start).Seconds() * 1000
+	return result, nil
+}
+
+func validateDNS(v DNSValidation, records []string) []ValidationOutcome {
+	var outcomes []ValidationOutcome
+
+	for _, pattern := range v.ValidateAnswerRRs {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			outcomes = append(outcomes, ValidationOutcome{Name: "answer_rr:" + pattern, Passed: false, Detail: fmt.Sprintf("invalid regex: %v", err)})
+			continue
+		}
+		matched := false
+		for _, rec := range records {
+			if re.MatchString(rec) {
+				matched = true
+				break
+			}
+		}
+		outcomes = append(outcomes, ValidationOutcome{Name: "answer_rr:" + pattern, Passed: matched})
+	}
+
+	for _, pattern := range v.ValidateAuthorityRRs {
+		outcomes = append(outcomes, ValidationOutcome{
+			Name:   "authority_rr:" + pattern,
+			Passed: false,
+			Detail: "no authority-section data available from this resolver",
+		})
+	}
+
+	return outcomes
+}
+
+func runTCP(_ context.Context, spec *TCPProbeSpec) (*ProbeResult, error) {
+	start := time.Now()
+	err := &sdk.TCPError{
+		Network: "tcp",
+		Address: spec.Address,
+		Err:     fmt.Errorf("tcp probes are not yet backed by a host function in this SDK build"),
+	}
+	return failedResult(start, err), nil
+}
+
+func runICMP(_ context.Context, spec *ICMPProbeSpec) (*ProbeResult, error) {
+	start := time.Now()
+	err := &sdk.NetworkOpError{
+		Operation: "icmp_probe",
+		Target:    spec.Host,
+		Err:       fmt.Errorf("ICMP probes are not available in this WASI sandbox build"),
+	}
+	return failedResult(start, err), nil
+}
+
+func failedResult(start time.Time, err error) *ProbeResult {
+	return &ProbeResult{
+		Success: false,
+		Error:   sdk.ToErrorDetail(err),
+		TotalMs: time.Since(start).Seconds() * 1000,
+	}
+}
+
+func allPassed(outcomes []ValidationOutcome) bool {
+	for _, o := range outcomes {
+		if !o.Passed {
+			return false
+		}
+	}
+	return true
+}
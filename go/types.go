@@ -35,6 +35,11 @@ type Metadata struct {
 	SDKVersion     string       `json:"sdk_version"`      // Auto-populated
 	MinHostVersion string       `json:"min_host_version"` // Minimum compatible host
 	Capabilities   []Capability `json:"capabilities"`
+
+	// CapabilitySchemaVersion pins the capabilityschema version this
+	// plugin targets, so the host can reject it if the pinned version is
+	// newer than what the host bundles.
+	CapabilitySchemaVersion string `json:"capability_schema_version,omitempty"`
 }
 
 // Capability describes a permission required by the plugin.
@@ -44,7 +49,12 @@ type Capability struct {
 }
 
 // ToErrorDetail converts a Go error to our structured ErrorDetail.
-// This function recognizes custom error types and categorizes them appropriately.
+// It recognizes the SDK's typed error structs first (which carry the
+// richest Code information), then falls back to checking errors.Is against
+// the sentinel errors in errors.go for handlers that wrap a plain error
+// instead of constructing one of those structs. Either way, if err wraps a
+// cause (via Unwrap), that cause is converted recursively into
+// ErrorDetail.Wrapped so the full chain survives the host boundary.
 func ToErrorDetail(err error) *ErrorDetail {
 	if err == nil {
 		return nil
@@ -56,15 +66,32 @@ func ToErrorDetail(err error) *ErrorDetail {
 		return wfError
 	}
 
-	// Check for custom SDK error types and categorize appropriately
+	detail := classifyTypedError(err)
+	if detail == nil {
+		detail = classifySentinelError(err)
+	}
+	if detail == nil {
+		detail = &ErrorDetail{Message: err.Error(), Type: "internal"}
+	}
+
+	if cause := errors.Unwrap(err); cause != nil {
+		detail.Wrapped = ToErrorDetail(cause)
+	}
+
+	return detail
+}
+
+// classifyTypedError matches err against the SDK's typed error structs
+// (errors.go), returning nil if none match.
+func classifyTypedError(err error) *ErrorDetail {
 	var (
-		netErr     *NetworkError
+		netErr     *NetworkOpError
 		dnsErr     *DNSError
 		httpErr    *HTTPError
 		tcpErr     *TCPError
 		timeoutErr *TimeoutError
 		capErr     *CapabilityError
-		confErr    *ConfigError
+		confErr    *ConfigValidationError
 		execErr    *ExecError
 		schemaErr  *SchemaError
 		memErr     *MemoryError
@@ -151,13 +178,21 @@ func ToErrorDetail(err error) *ErrorDetail {
 			Code:    "wire_format",
 		}
 	default:
-		// Generic error - categorize as internal
-		return &ErrorDetail{
-			Message: err.Error(),
-			Type:    "internal",
-			Code:    "",
+		return nil
+	}
+}
+
+// classifySentinelError matches err against the sentinel errors in
+// errors.go via errors.Is, for handlers that wrap a plain error (e.g.
+// fmt.Errorf("timed out after 5s: %w", ErrTimeout)) instead of constructing
+// one of the typed structs. Returns nil if none match.
+func classifySentinelError(err error) *ErrorDetail {
+	for _, st := range sentinelErrorTypes {
+		if errors.Is(err, st.sentinel) {
+			return &ErrorDetail{Message: err.Error(), Type: st.errType, Code: st.code}
 		}
 	}
+	return nil
 }
 
 // Success creates a successful Evidence with data.
@@ -174,6 +209,33 @@ func Failure(errType, message string) Evidence {
 	}
 }
 
+// ConfigError creates a failed Evidence for a configuration or input
+// validation failure, filing it under Type "config" rather than the
+// generic "internal" ToErrorDetail would assign to a bare error.
+func ConfigError(err error) Evidence {
+	return Evidence{
+		Status:    false,
+		Error:     &ErrorDetail{Message: err.Error(), Type: "config"},
+		Timestamp: time.Now(),
+	}
+}
+
+// NetworkError creates a failed Evidence for a network operation failure.
+// message describes what was being attempted (e.g. the target host:port);
+// err is the underlying cause and is preserved as ErrorDetail.Wrapped so
+// callers inspecting the result still see it.
+func NetworkError(message string, err error) Evidence {
+	return Evidence{
+		Status: false,
+		Error: &ErrorDetail{
+			Message: message,
+			Type:    "network",
+			Wrapped: ToErrorDetail(err),
+		},
+		Timestamp: time.Now(),
+	}
+}
+
 const (
 	// Version of the SDK
 	Version = "0.1.0-alpha"
@@ -39,70 +39,206 @@ type MXRecordWire struct {
 
 // HTTPRequestWire is the JSON wire format for an HTTP request from Guest to Host.
 type HTTPRequestWire struct {
-	Context ContextWireFormat   `json:"context"`
-	Method  string              `json:"method"`
-	URL     string              `json:"url"`
-	Headers map[string][]string `json:"headers,omitempty"`
-	Body    string              `json:"body,omitempty"` // Base64 encoded for binary, or plain string
+	Context    ContextWireFormat   `json:"context"`
+	Method     string              `json:"method"`
+	URL        string              `json:"url"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"` // Base64 encoded for binary, or plain string
+	Validation *HTTPValidationWire `json:"validation,omitempty"`
 	// TimeoutMs is implied by Context.TimeoutMs
 }
 
+// HTTPValidationWire is the JSON wire format for response validation
+// matchers. The host compiles each regexp once per request and streams the
+// response body through them instead of requiring the full body in memory,
+// and checks it against the full body even when the returned
+// HTTPResponseWire.Body is truncated by the host's body size limit.
+type HTTPValidationWire struct {
+	FailIfBodyMatchesRegexp    []string        `json:"fail_if_body_matches_regexp,omitempty"`
+	FailIfBodyNotMatchesRegexp []string        `json:"fail_if_body_not_matches_regexp,omitempty"`
+	FailIfHeaderMatches        []HeaderMatcher `json:"fail_if_header_matches,omitempty"`
+	FailIfHeaderNotMatches     []HeaderMatcher `json:"fail_if_header_not_matches,omitempty"`
+	ValidStatusCodes           []int           `json:"valid_status_codes,omitempty"`
+	ValidHTTPVersions          []string        `json:"valid_http_versions,omitempty"`
+}
+
+// HeaderMatcher matches a single response header, by name, against a regexp.
+type HeaderMatcher struct {
+	Header       string `json:"header"`
+	Regexp       string `json:"regexp"`
+	AllowMissing bool   `json:"allow_missing,omitempty"`
+}
+
 // HTTPResponseWire is the JSON wire format for an HTTP response from Host to Guest.
 type HTTPResponseWire struct {
-	StatusCode    int                 `json:"status_code"`
-	Headers       map[string][]string `json:"headers,omitempty"`
-	Body          string              `json:"body,omitempty"`           // Base64 encoded for binary, or plain string
-	BodyTruncated bool                `json:"body_truncated,omitempty"` // True if response body exceeded size limit
-	Error         *ErrorDetail        `json:"error,omitempty"`          // Structured error
+	StatusCode    int                       `json:"status_code"`
+	Headers       map[string][]string       `json:"headers,omitempty"`
+	Body          string                    `json:"body,omitempty"`           // Base64 encoded for binary, or plain string
+	BodyTruncated bool                      `json:"body_truncated,omitempty"` // True if response body exceeded size limit
+	Validation    *HTTPValidationResultWire `json:"validation,omitempty"`
+	Error         *ErrorDetail              `json:"error,omitempty"` // Structured error
+}
+
+// HTTPValidationResultWire is the JSON wire format reporting which
+// HTTPValidationWire matcher, if any, tripped. When a matcher fails, Error
+// is also populated with Type "validation" and a Code naming the matcher
+// (e.g. "body_matches:<pattern>"), so plugins can branch on failure class
+// without parsing Matcher themselves.
+type HTTPValidationResultWire struct {
+	Matcher string `json:"matcher,omitempty"` // e.g. "status_code", "header_matches:X-Foo", "body_matches:<pattern>"
+	Matched string `json:"matched,omitempty"` // Snippet that tripped the matcher, when applicable.
+	Offset  int64  `json:"offset,omitempty"`  // Byte offset of Matched within the body, for body matchers.
 }
 
 // TCPRequestWire is the JSON wire format for a TCP connection request from Guest to Host.
 type TCPRequestWire struct {
-	Context   ContextWireFormat `json:"context"`
-	Host      string            `json:"host"`
-	Port      string            `json:"port"`
-	TimeoutMs int               `json:"timeout_ms,omitempty"` // Optional timeout in milliseconds
-	TLS       bool              `json:"tls"`                  // Whether to use TLS
+	Context            ContextWireFormat `json:"context"`
+	Host               string            `json:"host"`
+	Port               string            `json:"port"`
+	TimeoutMs          int               `json:"timeout_ms,omitempty"` // Optional timeout in milliseconds
+	TLS                bool              `json:"tls"`                  // Whether to use TLS
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+	RootCAs            []string          `json:"root_cas,omitempty"`    // PEM-encoded CA certificates to trust instead of the system pool
+	ClientCert         string            `json:"client_cert,omitempty"` // PEM-encoded client certificate, for mTLS
+	ClientKey          string            `json:"client_key,omitempty"`  // PEM-encoded client private key, for mTLS
+	ServerName         string            `json:"server_name,omitempty"` // Overrides SNI; defaults to Host
+	ALPNProtocols      []string          `json:"alpn_protocols,omitempty"`
 }
 
 // TCPResponseWire is the JSON wire format for a TCP connection response from Host to Guest.
 type TCPResponseWire struct {
-	Connected       bool         `json:"connected"`
-	Address         string       `json:"address,omitempty"`
-	RemoteAddr      string       `json:"remote_addr,omitempty"`
-	LocalAddr       string       `json:"local_addr,omitempty"`
-	ResponseTimeMs  int64        `json:"response_time_ms,omitempty"`
-	TLS             bool         `json:"tls,omitempty"`
-	TLSVersion      string       `json:"tls_version,omitempty"`
-	TLSCipherSuite  string       `json:"tls_cipher_suite,omitempty"`
-	TLSServerName   string       `json:"tls_server_name,omitempty"`
-	TLSCertSubject  string       `json:"tls_cert_subject,omitempty"`
-	TLSCertIssuer   string       `json:"tls_cert_issuer,omitempty"`
-	TLSCertNotAfter *time.Time   `json:"tls_cert_not_after,omitempty"`
-	Error           *ErrorDetail `json:"error,omitempty"` // Structured error
+	Connected       bool           `json:"connected"`
+	Address         string         `json:"address,omitempty"`
+	RemoteAddr      string         `json:"remote_addr,omitempty"`
+	LocalAddr       string         `json:"local_addr,omitempty"`
+	ResponseTimeMs  int64          `json:"response_time_ms,omitempty"`
+	TLS             bool           `json:"tls,omitempty"`
+	TLSVersion      string         `json:"tls_version,omitempty"`
+	TLSCipherSuite  string         `json:"tls_cipher_suite,omitempty"`
+	TLSServerName   string         `json:"tls_server_name,omitempty"`
+	TLSCertSubject  string         `json:"tls_cert_subject,omitempty"`
+	TLSCertIssuer   string         `json:"tls_cert_issuer,omitempty"`
+	TLSCertNotAfter *time.Time     `json:"tls_cert_not_after,omitempty"`
+	TLSInspection   *TLSInspection `json:"tls_inspection,omitempty"` // Full chain/OCSP/SCT detail; populated whenever TLS is true
+	Error           *ErrorDetail   `json:"error,omitempty"`          // Structured error
 }
 
 // SMTPRequestWire is the JSON wire format for an SMTP connection request from Guest to Host.
 type SMTPRequestWire struct {
-	Context   ContextWireFormat `json:"context"`
-	Host      string            `json:"host"`
-	Port      string            `json:"port"`
-	TimeoutMs int               `json:"timeout_ms,omitempty"` // Optional timeout in milliseconds
-	TLS       bool              `json:"tls"`                  // Whether to use TLS (SMTPS on port 465)
-	StartTLS  bool              `json:"starttls"`             // Whether to use STARTTLS (upgrade to TLS)
+	Context            ContextWireFormat `json:"context"`
+	Host               string            `json:"host"`
+	Port               string            `json:"port"`
+	TimeoutMs          int               `json:"timeout_ms,omitempty"` // Optional timeout in milliseconds
+	TLS                bool              `json:"tls"`                  // Whether to use TLS (SMTPS on port 465)
+	StartTLS           bool              `json:"starttls"`             // Whether to use STARTTLS (upgrade to TLS)
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+	RootCAs            []string          `json:"root_cas,omitempty"`    // PEM-encoded CA certificates to trust instead of the system pool
+	ClientCert         string            `json:"client_cert,omitempty"` // PEM-encoded client certificate, for mTLS
+	ClientKey          string            `json:"client_key,omitempty"`  // PEM-encoded client private key, for mTLS
+	ServerName         string            `json:"server_name,omitempty"` // Overrides SNI; defaults to Host
+	ALPNProtocols      []string          `json:"alpn_protocols,omitempty"`
 }
 
 // SMTPResponseWire is the JSON wire format for an SMTP connection response from Host to Guest.
 type SMTPResponseWire struct {
-	Connected      bool         `json:"connected"`
-	Address        string       `json:"address,omitempty"`
-	Banner         string       `json:"banner,omitempty"` // SMTP banner message
-	ResponseTimeMs int64        `json:"response_time_ms,omitempty"`
-	TLS            bool         `json:"tls,omitempty"`
-	TLSVersion     string       `json:"tls_version,omitempty"`
-	TLSCipherSuite string       `json:"tls_cipher_suite,omitempty"`
-	TLSServerName  string       `json:"tls_server_name,omitempty"`
-	Error          *ErrorDetail `json:"error,omitempty"` // Structured error
+	Connected      bool           `json:"connected"`
+	Address        string         `json:"address,omitempty"`
+	Banner         string         `json:"banner,omitempty"` // SMTP banner message
+	ResponseTimeMs int64          `json:"response_time_ms,omitempty"`
+	TLS            bool           `json:"tls,omitempty"`
+	TLSVersion     string         `json:"tls_version,omitempty"`
+	TLSCipherSuite string         `json:"tls_cipher_suite,omitempty"`
+	TLSServerName  string         `json:"tls_server_name,omitempty"`
+	TLSInspection  *TLSInspection `json:"tls_inspection,omitempty"` // Full chain/OCSP/SCT detail; populated whenever TLS is true
+	Error          *ErrorDetail   `json:"error,omitempty"`          // Structured error
+}
+
+// TLSInspection carries the full detail of a negotiated TLS session, shared
+// by TCPResponseWire and SMTPResponseWire so both probes get chain, OCSP,
+// and CT data without duplicating the struct. The host populates this (and
+// OCSPStapled/SCTs specifically) even when certificate verification fails,
+// since the primary use case is diagnosing why a cert is bad, not just
+// whether it is.
+type TLSInspection struct {
+	NegotiatedProtocol string            `json:"negotiated_protocol,omitempty"` // e.g. "TLS 1.3"
+	NegotiatedCipher   string            `json:"negotiated_cipher,omitempty"`
+	NegotiatedALPN     string            `json:"negotiated_alpn,omitempty"`
+	ServerNameSent     string            `json:"server_name_sent,omitempty"`  // SNI actually sent on the wire
+	PeerCertificates   []CertificateInfo `json:"peer_certificates,omitempty"` // Ordered leaf-first, as presented by the peer
+	OCSPStapled        *OCSPInfo         `json:"ocsp_stapled,omitempty"`
+	SCTs               []SCTInfo         `json:"scts,omitempty"`               // Signed Certificate Timestamps from the CT extension
+	VerificationError  string            `json:"verification_error,omitempty"` // Set when chain verification failed despite inspection succeeding
+}
+
+// CertificateInfo describes a single certificate in a peer's chain.
+type CertificateInfo struct {
+	SubjectDN          string     `json:"subject_dn"`
+	IssuerDN           string     `json:"issuer_dn"`
+	SerialHex          string     `json:"serial_hex"`
+	NotBefore          time.Time  `json:"not_before"`
+	NotAfter           time.Time  `json:"not_after"`
+	SANs               []SANEntry `json:"sans,omitempty"`
+	KeyAlgorithm       string     `json:"key_algorithm"`      // e.g. "RSA", "ECDSA", "Ed25519"
+	KeySize            int        `json:"key_size,omitempty"` // Bits, where applicable
+	SignatureAlgorithm string     `json:"signature_algorithm"`
+	SHA256Fingerprint  string     `json:"sha256_fingerprint"`
+	IsCA               bool       `json:"is_ca"`
+	KeyUsage           int        `json:"key_usage"`               // x509.KeyUsage bitfield
+	ExtKeyUsage        []int      `json:"ext_key_usage,omitempty"` // x509.ExtKeyUsage values
+}
+
+// SANEntry is a single Subject Alternative Name, tagged with its type.
+type SANEntry struct {
+	Type  string `json:"type"` // "dns", "ip", "email", "uri"
+	Value string `json:"value"`
+}
+
+// OCSPInfo reports a stapled OCSP response observed during the TLS handshake.
+type OCSPInfo struct {
+	Status     string     `json:"status"` // "good", "revoked", "unknown"
+	ProducedAt *time.Time `json:"produced_at,omitempty"`
+	ThisUpdate *time.Time `json:"this_update,omitempty"`
+	NextUpdate *time.Time `json:"next_update,omitempty"`
+	Responder  string     `json:"responder,omitempty"`
+}
+
+// SCTInfo is a single Signed Certificate Timestamp from a Certificate
+// Transparency log, observed via the TLS extension, OCSP staple, or the
+// certificate itself.
+type SCTInfo struct {
+	LogID     string    `json:"log_id"` // Base64-encoded CT log ID
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ICMPRequestWire is the JSON wire format for an ICMP probe request from Guest to Host.
+type ICMPRequestWire struct {
+	Context           ContextWireFormat `json:"context"`
+	Target            string            `json:"target"`
+	Count             int               `json:"count"`
+	IntervalMs        int64             `json:"interval_ms,omitempty"`
+	PayloadSize       int               `json:"payload_size,omitempty"`
+	TimeoutMs         int64             `json:"timeout_ms,omitempty"`
+	PreferIPv6        bool              `json:"prefer_ipv6,omitempty"`
+	DoNotFragment     bool              `json:"do_not_fragment,omitempty"`
+	TOS               uint8             `json:"tos,omitempty"`
+	DontWaitLastReply bool              `json:"dont_wait_last_reply,omitempty"` // Don't extend the total probe time waiting out Timeout on the last packet.
+}
+
+// ICMPResponseWire is the JSON wire format for an ICMP probe response from Host to Guest.
+type ICMPResponseWire struct {
+	ResolvedSource string       `json:"resolved_source,omitempty"`
+	ResolvedDest   string       `json:"resolved_dest,omitempty"`
+	RTTsMs         []float64    `json:"rtts_ms,omitempty"` // One entry per packet; -1 means no reply.
+	PacketsSent    int          `json:"packets_sent"`
+	PacketsRecv    int          `json:"packets_recv"`
+	LossPercent    float64      `json:"loss_percent"`
+	MinRTTMs       float64      `json:"min_rtt_ms,omitempty"`
+	AvgRTTMs       float64      `json:"avg_rtt_ms,omitempty"`
+	MaxRTTMs       float64      `json:"max_rtt_ms,omitempty"`
+	StdDevRTTMs    float64      `json:"stddev_rtt_ms,omitempty"`
+	TTL            int          `json:"ttl,omitempty"`
+	UsedRawSocket  bool         `json:"used_raw_socket,omitempty"` // False when the unprivileged SOCK_DGRAM path was used.
+	Error          *ErrorDetail `json:"error,omitempty"`           // Structured error, e.g. "capability" when the host denies raw-socket fallback.
 }
 
 // ExecRequestWire is the JSON wire format for an exec request from Guest to Host.
@@ -124,6 +260,23 @@ type ExecResponseWire struct {
 	Error      *ErrorDetail `json:"error,omitempty"`
 }
 
+// ExecStreamChunk is a single frame of a streamed exec response, delivered
+// Host to Guest over the exec_stream ABI verb (alongside the single-shot
+// ExecRequestWire/ExecResponseWire verb) until a terminal frame carrying
+// EOF true and the exit metadata. This lets a plugin consume stdout/stderr
+// incrementally instead of buffering the full output, for commands that
+// are long-running or produce more output than the WASM memory budget
+// allows (tailing logs, traceroute, long synthetic probes).
+type ExecStreamChunk struct {
+	Seq        int64        `json:"seq"`            // Monotonically increasing per stream, starting at 0
+	Stream     string       `json:"stream"`         // "stdout" or "stderr"
+	Data       []byte       `json:"data,omitempty"` // Base64 encoded
+	EOF        bool         `json:"eof"`            // True on the terminal frame; ExitCode/DurationMs are set
+	ExitCode   *int         `json:"exit_code,omitempty"`
+	DurationMs *int64       `json:"duration_ms,omitempty"`
+	Error      *ErrorDetail `json:"error,omitempty"` // e.g. Type "capability" when max_output_bytes/max_chunk_bytes is exceeded
+}
+
 // ErrorDetail provides structured error information, consistent across host and SDK.
 // Error Types: "network", "timeout", "config", "panic", "capability", "validation", "internal"
 type ErrorDetail struct {
@@ -133,7 +286,16 @@ type ErrorDetail struct {
 	IsTimeout  bool         `json:"is_timeout,omitempty"`   // For network errors
 	IsNotFound bool         `json:"is_not_found,omitempty"` // For network/DNS errors
 	Wrapped    *ErrorDetail `json:"wrapped,omitempty"`
-	Stack      []byte       `json:"stack,omitempty"` // Stack trace for panic errors (SDK only)
+	Stack      []byte       `json:"stack,omitempty"`  // Stack trace for panic errors (SDK only)
+	Fields     []FieldError `json:"fields,omitempty"` // Per-field failures, for Type == "validation"
+}
+
+// FieldError is one failing field from a validation pass - a JSON Schema
+// constraint or a struct-tag rule - addressed by a JSON pointer into the
+// document that was validated (e.g. "/timeout_ms").
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
 }
 
 // Error implements the error interface for ErrorDetail.
@@ -0,0 +1,130 @@
+package hostfuncs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterByFamily(t *testing.T) {
+	v4 := net.ParseIP("93.184.216.34")
+	v6 := net.ParseIP("2606:2800:220:1:248:1893:25c8:1946")
+
+	assert.Equal(t, []net.IP{v4}, filterByFamily([]net.IP{v4, v6}, IPv4Only))
+	assert.Equal(t, []net.IP{v6}, filterByFamily([]net.IP{v4, v6}, IPv6Only))
+	assert.Equal(t, []net.IP{v6, v4}, filterByFamily([]net.IP{v4, v6}, DualStack))
+}
+
+func TestDialHappyEyeballs_FallsBackWhenFirstFails(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	unreachable := net.ParseIP("203.0.113.1") // TEST-NET-3, non-routable
+	listening := net.ParseIP("127.0.0.1")
+
+	conn, err := dialHappyEyeballs(context.Background(), "tcp", []net.IP{unreachable, listening}, port, 20*time.Millisecond)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+// fakeConn is a no-op net.Conn that records whether Close was called, so
+// tests can assert a losing connection from dialHappyEyeballsWithDialer
+// actually gets cleaned up.
+type fakeConn struct {
+	net.Conn
+	closed chan struct{}
+}
+
+func (c *fakeConn) Close() error {
+	close(c.closed)
+	return nil
+}
+
+// TestCloseLosingConns covers the common, healthy dual-stack case where
+// more than one candidate succeeds (not just the documented
+// fallback-on-failure case): every connection that wins the race after the
+// first must still be closed rather than leaked.
+func TestCloseLosingConns(t *testing.T) {
+	loser1 := &fakeConn{closed: make(chan struct{})}
+	loser2 := &fakeConn{closed: make(chan struct{})}
+	failed := &fakeConn{closed: make(chan struct{})}
+
+	results := make(chan dialResult, 3)
+	results <- dialResult{conn: loser1}
+	results <- dialResult{conn: loser2}
+	results <- dialResult{err: errors.New("dial failed")}
+
+	closeLosingConns(results, 3)
+
+	select {
+	case <-loser1.closed:
+	default:
+		t.Error("loser1 was never closed")
+	}
+	select {
+	case <-loser2.closed:
+	default:
+		t.Error("loser2 was never closed")
+	}
+	select {
+	case <-failed.closed:
+		t.Error("a failed dial's conn should never be touched")
+	default:
+	}
+}
+
+func TestDialHappyEyeballs_ClosesLoserWhenBothSucceed(t *testing.T) {
+	winner := &fakeConn{closed: make(chan struct{})}
+	loser := &fakeConn{closed: make(chan struct{})}
+
+	// The first IP's dial is slow but still succeeds - like a connection
+	// that was already in flight when a faster candidate won the race -
+	// so it must still be closed once it lands rather than leaked.
+	dial := func(ctx context.Context, network string, ip net.IP, port string) (net.Conn, error) {
+		if ip.Equal(net.ParseIP("127.0.0.1")) {
+			time.Sleep(30 * time.Millisecond)
+			return loser, nil
+		}
+		return winner, nil
+	}
+
+	conn, err := dialHappyEyeballsWithDialer(
+		context.Background(), "tcp",
+		[]net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("127.0.0.2")},
+		"80", 0, dial,
+	)
+	require.NoError(t, err)
+	assert.Same(t, net.Conn(winner), conn)
+
+	select {
+	case <-loser.closed:
+	case <-time.After(time.Second):
+		t.Fatal("losing connection was never closed")
+	}
+
+	select {
+	case <-winner.closed:
+		t.Fatal("winning connection was closed")
+	default:
+	}
+}
+
+func TestDnsPinCache_NegativeCaching(t *testing.T) {
+	cache := newDNSPinCache()
+	_, err := cache.get("this-host-does-not-resolve.invalid", false, DualStack)
+	require.Error(t, err)
+
+	// Second lookup should hit the cached negative entry rather than
+	// re-resolving (and should still return an error, not panic).
+	_, err2 := cache.get("this-host-does-not-resolve.invalid", false, DualStack)
+	require.Error(t, err2)
+}
@@ -1,13 +1,13 @@
 package hostfuncs
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -35,6 +35,11 @@ type HTTPRequest struct {
 
 	// MaxRedirects is the maximum number of redirects to follow. Default is 10.
 	MaxRedirects int `json:"max_redirects,omitempty"`
+
+	// Validation configures response matchers checked before the response
+	// is returned, so a plugin doesn't have to reimplement regex matching
+	// over a body it would otherwise have to fetch in full.
+	Validation HTTPValidation `json:"validation,omitempty"`
 }
 
 // HTTPResponse contains the result of an HTTP request.
@@ -59,6 +64,16 @@ type HTTPResponse struct {
 
 	// BodyTruncated indicates if the body was truncated due to size limits.
 	BodyTruncated bool `json:"body_truncated,omitempty"`
+
+	// Attempts is the number of request attempts made, including the first.
+	Attempts int `json:"attempts,omitempty"`
+
+	// TotalElapsedMs is the total time spent across all attempts, including backoff waits.
+	TotalElapsedMs int64 `json:"total_elapsed_ms,omitempty"`
+
+	// Validation reports the outcome of Request.Validation's matchers, nil
+	// if no matchers were configured.
+	Validation *HTTPValidationResult `json:"validation,omitempty"`
 }
 
 // HTTPError represents an HTTP request error.
@@ -76,22 +91,97 @@ func (e *HTTPError) Error() string {
 type HTTPOption func(*httpConfig)
 
 type httpConfig struct {
-	tlsConfig       *tls.Config
-	timeout         time.Duration
-	maxRedirects    int
-	maxBodySize     int64
-	followRedirects bool
-	ssrfProtection  bool
-	allowPrivate    bool
+	tlsConfig          *tls.Config
+	timeout            time.Duration
+	maxRedirects       int
+	maxBodySize        int64
+	followRedirects    bool
+	ssrfProtection     bool
+	allowPrivate       bool
+	retry              RetryConfig
+	middleware         []HTTPMiddleware
+	addressFamily      AddressFamily
+	happyEyeballsDelay time.Duration
 }
 
 func defaultHTTPConfig() httpConfig {
 	return httpConfig{
-		timeout:         30 * time.Second,
-		maxRedirects:    10,
-		followRedirects: true,
-		tlsConfig:       nil,
-		maxBodySize:     10 * 1024 * 1024, // 10MB
+		timeout:            30 * time.Second,
+		maxRedirects:       10,
+		followRedirects:    true,
+		tlsConfig:          nil,
+		maxBodySize:        10 * 1024 * 1024, // 10MB
+		retry:              defaultRetryConfig(),
+		addressFamily:      DualStack,
+		happyEyeballsDelay: defaultHappyEyeballsDelay,
+	}
+}
+
+// RetryConfig controls the retry-with-backoff behavior of PerformHTTPRequest.
+// It is modeled after the retry policy used by OTLP/HTTP exporters: an
+// exponential backoff with a cap, bounded by a total elapsed-time budget.
+type RetryConfig struct {
+	// Enabled turns retries on. Default is false (no retries).
+	Enabled bool
+
+	// InitialInterval is the backoff before the first retry.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the backoff between retries.
+	MaxInterval time.Duration
+
+	// MaxElapsedTime bounds the total time spent retrying, across all attempts.
+	MaxElapsedTime time.Duration
+
+	// Multiplier grows the backoff interval after each attempt.
+	Multiplier float64
+
+	// RetryableStatusCodes lists HTTP status codes that should trigger a retry.
+	// Defaults to 429, 502, 503, 504.
+	RetryableStatusCodes []int
+}
+
+func defaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		Enabled:              false,
+		InitialInterval:      500 * time.Millisecond,
+		MaxInterval:          30 * time.Second,
+		MaxElapsedTime:       2 * time.Minute,
+		Multiplier:           2.0,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+func (c RetryConfig) isRetryableStatus(code int) bool {
+	for _, sc := range c.RetryableStatusCodes {
+		if sc == code {
+			return true
+		}
+	}
+	return false
+}
+
+// WithHTTPRetry enables retry-with-backoff for PerformHTTPRequest using the
+// given policy. Zero-value fields fall back to their documented defaults.
+func WithHTTPRetry(cfg RetryConfig) HTTPOption {
+	return func(c *httpConfig) {
+		cfg.Enabled = true
+		if cfg.InitialInterval <= 0 {
+			cfg.InitialInterval = defaultRetryConfig().InitialInterval
+		}
+		if cfg.MaxInterval <= 0 {
+			cfg.MaxInterval = defaultRetryConfig().MaxInterval
+		}
+		if cfg.MaxElapsedTime <= 0 {
+			cfg.MaxElapsedTime = defaultRetryConfig().MaxElapsedTime
+		}
+		if cfg.Multiplier <= 1 {
+			cfg.Multiplier = defaultRetryConfig().Multiplier
+		}
+		if len(cfg.RetryableStatusCodes) == 0 {
+			cfg.RetryableStatusCodes = defaultRetryConfig().RetryableStatusCodes
+		}
+		c.retry = cfg
 	}
 }
 
@@ -140,10 +230,49 @@ func WithHTTPSSRFProtection(allowPrivate bool) HTTPOption {
 	}
 }
 
-// dnsPinnedEntry represents a validated and pinned DNS resolution.
+// AddressFamily selects which IP address family(ies) the SSRF-protected
+// transport is allowed to dial.
+type AddressFamily int
+
+const (
+	// DualStack attempts both IPv6 and IPv4 addresses using Happy Eyeballs
+	// (RFC 8305), preferring IPv6. This is the default.
+	DualStack AddressFamily = iota
+	// IPv4Only restricts dialing to A records.
+	IPv4Only
+	// IPv6Only restricts dialing to AAAA records.
+	IPv6Only
+)
+
+// defaultHappyEyeballsDelay is the stagger between connection attempts to
+// successive addresses, per RFC 8305's recommended 250ms.
+const defaultHappyEyeballsDelay = 250 * time.Millisecond
+
+// WithHTTPAddressFamily forces the SSRF-protected transport to dial only
+// IPv4, only IPv6, or both (DualStack, the default).
+func WithHTTPAddressFamily(af AddressFamily) HTTPOption {
+	return func(c *httpConfig) {
+		c.addressFamily = af
+	}
+}
+
+// WithHTTPHappyEyeballsDelay sets the stagger between connection attempts to
+// successive addresses when DualStack dialing. Default is 250ms.
+func WithHTTPHappyEyeballsDelay(d time.Duration) HTTPOption {
+	return func(c *httpConfig) {
+		if d > 0 {
+			c.happyEyeballsDelay = d
+		}
+	}
+}
+
+// dnsPinnedEntry represents a validated and pinned DNS resolution. A failed
+// resolution/validation is cached too (err set, ips nil) so a poisoned
+// record doesn't cause a fresh SSRF check on every dial.
 type dnsPinnedEntry struct {
-	resolvedIP string
-	timestamp  time.Time
+	ips       []net.IP
+	err       error
+	timestamp time.Time
 }
 
 // dnsPinCache caches validated DNS resolutions to prevent rebinding attacks
@@ -161,47 +290,98 @@ func newDNSPinCache() *dnsPinCache {
 	}
 }
 
-func (c *dnsPinCache) get(hostname string, allowPrivate bool) (string, error) {
-	// Check cache first
+// get returns the set of validated, pinned IPs for hostname, filtered to
+// the requested address family. Only IPs in the cached set are ever dialed
+// for the entry's TTL, which preserves DNS-rebinding protection even though
+// multiple addresses are now pinned per host.
+func (c *dnsPinCache) get(hostname string, allowPrivate bool, af AddressFamily) ([]net.IP, error) {
 	c.mu.RLock()
 	entry, found := c.entries[hostname]
 	c.mu.RUnlock()
 
-	if found && time.Since(entry.timestamp) < c.ttl {
-		return entry.resolvedIP, nil
+	if !found || time.Since(entry.timestamp) >= c.ttl {
+		entry = c.resolveAndValidate(hostname, allowPrivate)
 	}
 
-	// Not in cache or expired - resolve and validate
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	ips := filterByFamily(entry.ips, af)
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("SSRF protection: no addresses for %s match the requested address family", hostname)
+	}
+	return ips, nil
+}
+
+// resolveAndValidate resolves hostname to its A/AAAA records, validates each
+// individually, and caches the result (positive or negative) for the TTL.
+func (c *dnsPinCache) resolveAndValidate(hostname string, allowPrivate bool) dnsPinnedEntry {
 	var opts []NetfilterOption
 	if allowPrivate {
 		opts = append(opts, WithBlockPrivate(false), WithBlockLocalhost(false))
 	}
-	result := ValidateAddress(hostname, opts...)
 
-	if !result.Allowed {
-		return "", fmt.Errorf("SSRF protection: %s", result.Reason)
+	addrs, lookupErr := net.LookupIP(hostname)
+	if lookupErr != nil {
+		entry := dnsPinnedEntry{err: fmt.Errorf("SSRF protection: %w", lookupErr), timestamp: time.Now()}
+		c.store(hostname, entry)
+		return entry
 	}
 
-	resolvedIP := result.ResolvedIP
-	if resolvedIP == "" {
-		resolvedIP = hostname
+	var validated []net.IP
+	for _, ip := range addrs {
+		result := ValidateAddress(ip.String(), opts...)
+		if result.Allowed {
+			validated = append(validated, ip)
+		}
 	}
 
-	// Cache the validated resolution
-	c.mu.Lock()
-	c.entries[hostname] = dnsPinnedEntry{
-		resolvedIP: resolvedIP,
-		timestamp:  time.Now(),
+	var entry dnsPinnedEntry
+	if len(validated) == 0 {
+		entry = dnsPinnedEntry{err: fmt.Errorf("SSRF protection: no allowed addresses for %s", hostname), timestamp: time.Now()}
+	} else {
+		entry = dnsPinnedEntry{ips: validated, timestamp: time.Now()}
 	}
+	c.store(hostname, entry)
+	return entry
+}
+
+func (c *dnsPinCache) store(hostname string, entry dnsPinnedEntry) {
+	c.mu.Lock()
+	c.entries[hostname] = entry
 	c.mu.Unlock()
+}
+
+// filterByFamily returns the subset of ips matching af, preferring IPv6
+// first for DualStack (the RFC 8305 default preference).
+func filterByFamily(ips []net.IP, af AddressFamily) []net.IP {
+	var v4, v6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+		} else {
+			v6 = append(v6, ip)
+		}
+	}
 
-	return resolvedIP, nil
+	switch af {
+	case IPv4Only:
+		return v4
+	case IPv6Only:
+		return v6
+	default:
+		return append(v6, v4...)
+	}
 }
 
 // ssrfProtectedTransport wraps http.Transport with DNS pinning and SSRF protection
 // while preserving connection pooling for performance.
-func newSSRFProtectedTransport(allowPrivate bool) *http.Transport {
+func newSSRFProtectedTransport(allowPrivate bool, af AddressFamily, happyEyeballsDelay time.Duration) *http.Transport {
 	cache := newDNSPinCache()
+	if happyEyeballsDelay <= 0 {
+		happyEyeballsDelay = defaultHappyEyeballsDelay
+	}
 
 	transport := &http.Transport{
 		ForceAttemptHTTP2:     true,
@@ -209,8 +389,8 @@ func newSSRFProtectedTransport(allowPrivate bool) *http.Transport {
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
-		// 1. Resolve DNS once per hostname and cache the validated IP
-		// 2. All subsequent dials use the cached IP (prevents DNS rebinding)
+		// 1. Resolve DNS once per hostname and cache the validated IP set
+		// 2. All subsequent dials pick from the cached set (prevents DNS rebinding)
 		// 3. Transport reuses connections when possible (maintains performance)
 		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
 			// Extract hostname from address
@@ -220,20 +400,13 @@ func newSSRFProtectedTransport(allowPrivate bool) *http.Transport {
 				port = ""
 			}
 
-			// Get pinned IP from cache (validates on first access)
-			resolvedIP, err := cache.get(host, allowPrivate)
+			// Get pinned IPs from cache (validates on first access)
+			ips, err := cache.get(host, allowPrivate, af)
 			if err != nil {
 				return nil, err
 			}
 
-			// Reconstruct address with pinned IP
-			targetAddr := resolvedIP
-			if port != "" {
-				targetAddr = net.JoinHostPort(resolvedIP, port)
-			}
-
-			// Dial the pinned, validated address
-			return (&net.Dialer{}).DialContext(ctx, network, targetAddr)
+			return dialHappyEyeballs(ctx, network, ips, port, happyEyeballsDelay)
 		},
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
@@ -248,6 +421,93 @@ func newSSRFProtectedTransport(allowPrivate bool) *http.Transport {
 	return transport
 }
 
+// dialResult is one dial attempt's outcome, reported on dialHappyEyeballs's
+// results channel.
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dialHappyEyeballs attempts the given (already family-ordered) IPs in
+// parallel with a small stagger between each, per RFC 8305, returning the
+// first successful connection and canceling the rest. ips must be
+// non-empty.
+func dialHappyEyeballs(ctx context.Context, network string, ips []net.IP, port string, delay time.Duration) (net.Conn, error) {
+	return dialHappyEyeballsWithDialer(ctx, network, ips, port, delay, dialPinned)
+}
+
+// dialHappyEyeballsWithDialer is dialHappyEyeballs with the per-IP dial
+// function overridable, so tests can race fake dialers without touching the
+// network.
+func dialHappyEyeballsWithDialer(ctx context.Context, network string, ips []net.IP, port string, delay time.Duration, dial func(ctx context.Context, network string, ip net.IP, port string) (net.Conn, error)) (net.Conn, error) {
+	if len(ips) == 1 {
+		return dial(ctx, network, ips[0], port)
+	}
+
+	dialCtx, cancel := context.WithCancel(ctx)
+
+	results := make(chan dialResult, len(ips))
+
+	for i, ip := range ips {
+		ip := ip
+		i := i
+		go func() {
+			if i > 0 {
+				timer := time.NewTimer(time.Duration(i) * delay)
+				defer timer.Stop()
+				select {
+				case <-dialCtx.Done():
+					results <- dialResult{err: dialCtx.Err()}
+					return
+				case <-timer.C:
+				}
+			}
+			conn, err := dial(dialCtx, network, ip, port)
+			results <- dialResult{conn: conn, err: err}
+		}()
+	}
+
+	var firstErr error
+	remaining := len(ips)
+	for remaining > 0 {
+		res := <-results
+		remaining--
+		if res.err == nil {
+			cancel()
+			if remaining > 0 {
+				go closeLosingConns(results, remaining)
+			}
+			return res.conn, nil
+		}
+		if firstErr == nil {
+			firstErr = res.err
+		}
+	}
+	cancel()
+	return nil, firstErr
+}
+
+// closeLosingConns drains the remaining in-flight dials started by
+// dialHappyEyeballsWithDialer after a winner has already been chosen and
+// closes any connection that still succeeds, so a healthy dual-stack race -
+// not just the documented fallback-on-failure case - never leaks a socket.
+func closeLosingConns(results chan dialResult, remaining int) {
+	for ; remaining > 0; remaining-- {
+		if res := <-results; res.err == nil {
+			res.conn.Close()
+		}
+	}
+}
+
+// dialPinned dials a single validated, pinned IP.
+func dialPinned(ctx context.Context, network string, ip net.IP, port string) (net.Conn, error) {
+	targetAddr := ip.String()
+	if port != "" {
+		targetAddr = net.JoinHostPort(ip.String(), port)
+	}
+	return (&net.Dialer{}).DialContext(ctx, network, targetAddr)
+}
+
 // PerformHTTPRequest performs an HTTP request.
 // This is a pure Go implementation with no WASM runtime dependencies.
 //
@@ -270,12 +530,120 @@ func PerformHTTPRequest(ctx context.Context, req HTTPRequest, opts ...HTTPOption
 		return HTTPResponse{Error: err}
 	}
 
-	// Apply timeout to context
-	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
-	defer cancel()
+	if !cfg.retry.Enabled {
+		ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+		resp := executeHTTPRequest(ctx, req, cfg)
+		resp.Attempts = 1
+		return resp
+	}
+
+	return performHTTPRequestWithRetry(ctx, req, cfg)
+}
+
+// performHTTPRequestWithRetry executes the request, retrying on classified
+// transient failures using exponential backoff bounded by MaxElapsedTime.
+func performHTTPRequestWithRetry(ctx context.Context, req HTTPRequest, cfg httpConfig) HTTPResponse {
+	start := time.Now()
+	interval := cfg.retry.InitialInterval
+
+	var resp HTTPResponse
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.timeout)
+		if attempt > 1 {
+			attemptCtx = context.WithValue(attemptCtx, retryAttemptContextKey{}, true)
+		}
+		resp = executeHTTPRequest(attemptCtx, req, cfg)
+		cancel()
+		resp.Attempts = attempt
+		resp.TotalElapsedMs = time.Since(start).Milliseconds()
+
+		wait, retryable := shouldRetry(resp, cfg.retry, time.Since(start))
+		if !retryable {
+			return resp
+		}
+
+		if wait <= 0 {
+			wait = interval
+		}
+		if wait > cfg.retry.MaxInterval {
+			wait = cfg.retry.MaxInterval
+		}
+
+		if time.Since(start)+wait >= cfg.retry.MaxElapsedTime {
+			return resp
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			resp.TotalElapsedMs = time.Since(start).Milliseconds()
+			return resp
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * cfg.retry.Multiplier)
+		if interval > cfg.retry.MaxInterval {
+			interval = cfg.retry.MaxInterval
+		}
+	}
+}
+
+// shouldRetry classifies a response/error outcome and, when retryable,
+// returns the wait duration to honor (from Retry-After, if present).
+func shouldRetry(resp HTTPResponse, cfg RetryConfig, elapsed time.Duration) (time.Duration, bool) {
+	if elapsed >= cfg.MaxElapsedTime {
+		return 0, false
+	}
+
+	if resp.Error != nil {
+		switch resp.Error.Code {
+		case "SSRF_BLOCKED", "INVALID_REQUEST":
+			return 0, false
+		case "TIMEOUT":
+			return 0, elapsed < cfg.MaxElapsedTime
+		default:
+			return 0, true
+		}
+	}
+
+	if cfg.isRetryableStatus(resp.StatusCode) {
+		return retryAfterWait(resp.Headers, cfg.MaxInterval), true
+	}
+
+	return 0, false
+}
+
+// retryAfterWait parses a Retry-After header (delta-seconds or HTTP-date)
+// from the response headers, capped at maxWait. Returns 0 if absent/invalid.
+func retryAfterWait(headers map[string][]string, maxWait time.Duration) time.Duration {
+	values := headers["Retry-After"]
+	if len(values) == 0 {
+		return 0
+	}
+	v := strings.TrimSpace(values[0])
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		wait := time.Duration(secs) * time.Second
+		if wait > maxWait {
+			return maxWait
+		}
+		return wait
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			return 0
+		}
+		if wait > maxWait {
+			return maxWait
+		}
+		return wait
+	}
 
-	// Create and execute HTTP request
-	return executeHTTPRequest(ctx, req, cfg)
+	return 0
 }
 
 // applyRequestConfig overrides default config with request-specific values.
@@ -306,49 +674,80 @@ func validateHTTPRequest(req *HTTPRequest) *HTTPError {
 }
 
 // executeHTTPRequest creates the HTTP client, performs the request, and reads the response.
+// executeHTTPRequest performs the request via the streaming primitive and
+// drains it into a fully-buffered HTTPResponse, preserving the historical
+// truncate-rather-than-fail behavior of PerformHTTPRequest.
 func executeHTTPRequest(ctx context.Context, req HTTPRequest, cfg httpConfig) HTTPResponse {
-	// Create HTTP request
-	var body io.Reader
-	if len(req.Body) > 0 {
-		body = bytes.NewReader(req.Body)
-	}
+	// Raise the stream's hard cap by one byte so DrainToBuffer can read
+	// maxBodySize+1 bytes to detect truncation without tripping ErrBodyTooLarge.
+	streamCfg := cfg
+	streamCfg.maxBodySize = cfg.maxBodySize + 1
 
-	httpReq, err := http.NewRequestWithContext(ctx, strings.ToUpper(req.Method), req.URL, body)
+	matchers, err := compileBodyMatchers(req.Validation)
 	if err != nil {
-		return HTTPResponse{
-			Error: &HTTPError{
-				Code:    "INVALID_REQUEST",
-				Message: err.Error(),
-			},
-		}
+		return HTTPResponse{Error: &HTTPError{Code: "INVALID_VALIDATION_CONFIG", Message: err.Error()}}
 	}
-
-	// Set headers
-	for k, v := range req.Headers {
-		httpReq.Header.Set(k, v)
+	if len(matchers) > 0 {
+		// Body matchers must see past the returned body's truncation
+		// point, so the underlying reader needs a much higher hard cap
+		// than the one the returned buffer is truncated at.
+		streamCfg.maxBodySize = maxBodyValidationScan
 	}
 
-	// Create client with redirect policy
-	client := createHTTPClient(cfg)
-
-	// Perform request
 	start := time.Now()
-	resp, err := client.Do(httpReq)
+	streamResp, err := performHTTPRequestStream(ctx, req, streamCfg)
 	latency := time.Since(start)
-
 	if err != nil {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return HTTPResponse{LatencyMs: latency.Milliseconds(), Error: httpErr}
+		}
 		return handleHTTPError(err, ctx, latency)
 	}
-	defer func() { _ = resp.Body.Close() }()
+	defer func() { _ = streamResp.Body.Close() }()
+
+	var validationResult *HTTPValidationResult
+	if !req.Validation.isZero() {
+		validationResult = validateStatusAndHeaders(streamResp, req.Validation)
+	}
+
+	var body []byte
+	var truncated bool
+	if len(matchers) > 0 && validationResult == nil {
+		var bodyMatchResult *HTTPValidationResult
+		body, truncated, bodyMatchResult, err = drainAndValidate(streamResp.Body, cfg.maxBodySize, matchers)
+		validationResult = bodyMatchResult
+	} else {
+		body, truncated, err = DrainToBuffer(streamResp, cfg.maxBodySize)
+	}
+	if err != nil {
+		return HTTPResponse{
+			StatusCode: streamResp.StatusCode,
+			Headers:    streamResp.Headers,
+			LatencyMs:  latency.Milliseconds(),
+			Error: &HTTPError{
+				Code:    "READ_BODY_FAILED",
+				Message: err.Error(),
+			},
+		}
+	}
 
-	return readHTTPResponse(resp, latency, cfg.maxBodySize)
+	return HTTPResponse{
+		StatusCode:    streamResp.StatusCode,
+		Headers:       streamResp.Headers,
+		Body:          body,
+		BodyTruncated: truncated,
+		LatencyMs:     latency.Milliseconds(),
+		Proto:         streamResp.Proto,
+		Validation:    validationResult,
+	}
 }
 
 // createHTTPClient creates an HTTP client with the appropriate redirect policy.
 func createHTTPClient(cfg httpConfig) *http.Client {
 	var transport *http.Transport
 	if cfg.ssrfProtection {
-		transport = newSSRFProtectedTransport(cfg.allowPrivate)
+		transport = newSSRFProtectedTransport(cfg.allowPrivate, cfg.addressFamily, cfg.happyEyeballsDelay)
 	} else {
 		transport = &http.Transport{
 			ForceAttemptHTTP2:     true,
@@ -359,9 +758,14 @@ func createHTTPClient(cfg httpConfig) *http.Client {
 		}
 	}
 
+	var rt HTTPRoundTripper = transport
+	for i := len(cfg.middleware) - 1; i >= 0; i-- {
+		rt = cfg.middleware[i](rt)
+	}
+
 	client := &http.Client{
 		Timeout:   cfg.timeout,
-		Transport: transport,
+		Transport: rt,
 	}
 
 	if !cfg.followRedirects {
@@ -404,36 +808,3 @@ func handleHTTPError(err error, ctx context.Context, latency time.Duration) HTTP
 		},
 	}
 }
-
-// readHTTPResponse reads and returns the HTTP response body with size limiting.
-func readHTTPResponse(resp *http.Response, latency time.Duration, maxBodySize int64) HTTPResponse {
-	// Read response body with size limit
-	bodyReader := io.LimitReader(resp.Body, maxBodySize+1)
-	respBody, err := io.ReadAll(bodyReader)
-	if err != nil {
-		return HTTPResponse{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			LatencyMs:  latency.Milliseconds(),
-			Error: &HTTPError{
-				Code:    "READ_BODY_FAILED",
-				Message: err.Error(),
-			},
-		}
-	}
-
-	truncated := false
-	if int64(len(respBody)) > maxBodySize {
-		respBody = respBody[:maxBodySize]
-		truncated = true
-	}
-
-	return HTTPResponse{
-		StatusCode:    resp.StatusCode,
-		Headers:       resp.Header,
-		Body:          respBody,
-		BodyTruncated: truncated,
-		LatencyMs:     latency.Milliseconds(),
-		Proto:         resp.Proto,
-	}
-}
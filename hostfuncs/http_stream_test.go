@@ -0,0 +1,104 @@
+package hostfuncs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformHTTPRequestStream_ReadsBodyIncrementally(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("hello stream"))
+	}))
+	defer srv.Close()
+
+	resp, err := PerformHTTPRequestStream(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello stream", string(data))
+}
+
+func TestPerformHTTPRequestStream_ErrBodyTooLarge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("this body is far too long for the configured cap"))
+	}))
+	defer srv.Close()
+
+	resp, err := PerformHTTPRequestStream(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPMaxBodySize(8),
+	)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestPerformHTTPRequestStream_TimeoutSpansFullRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		flusher, ok := w.(http.Flusher)
+		_, _ = w.Write([]byte("first-chunk"))
+		if ok {
+			flusher.Flush()
+		}
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("second-chunk"))
+	}))
+	defer srv.Close()
+
+	resp, err := PerformHTTPRequestStream(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPRequestTimeout(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	require.Error(t, err)
+}
+
+func TestDrainToBuffer_TruncatesWithoutError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	resp, err := PerformHTTPRequestStream(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, truncated, err := DrainToBuffer(resp, 5)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, "01234", string(data))
+}
+
+func TestExecuteHTTPRequest_StillTruncatesViaBufferedAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPMaxBodySize(5),
+	)
+
+	require.Nil(t, resp.Error)
+	assert.True(t, resp.BodyTruncated)
+	assert.Equal(t, "01234", string(resp.Body))
+}
@@ -0,0 +1,94 @@
+package hostfuncs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformHTTPRequest_RetrySucceedsAfterTransientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPRetry(RetryConfig{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     5 * time.Millisecond,
+			MaxElapsedTime:  time.Second,
+		}),
+	)
+
+	require.Nil(t, resp.Error)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, resp.Attempts)
+}
+
+func TestPerformHTTPRequest_RetryHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	start := time.Now()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPRetry(RetryConfig{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     5 * time.Second,
+			MaxElapsedTime:  5 * time.Second,
+		}),
+	)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestPerformHTTPRequest_RetryDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPRetry(RetryConfig{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond, MaxElapsedTime: time.Second}),
+	)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, 1, resp.Attempts)
+	assert.EqualValues(t, 1, calls)
+}
+
+func TestPerformHTTPRequest_NoRetryByDefault(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL})
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 1, resp.Attempts)
+	assert.EqualValues(t, 1, calls)
+}
@@ -0,0 +1,282 @@
+package hostfuncs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// maxBodyValidationScan bounds how much of the response body the streaming
+// body matchers will read past the point where HTTPResponse.Body is
+// truncated. This keeps validation bounded against a hostile or
+// misbehaving server while still honoring "validate the full stream, not
+// just the truncated snapshot" for any response of a realistic size.
+const maxBodyValidationScan = 256 * 1024 * 1024 // 256MB
+
+// bodyMatchWindow is the size of the sliding buffer runBodyMatchers keeps
+// in memory while scanning: large enough that a reasonable regex pattern
+// won't be missed across a read-boundary, small enough that memory use
+// stays flat regardless of total body size.
+const bodyMatchWindow = 64 * 1024
+const bodyMatchReadSize = 32 * 1024
+
+// HeaderMatcher matches a single response header, by name, against a regexp.
+type HeaderMatcher struct {
+	// Header is the header name, matched case-insensitively (canonicalized
+	// the way net/http canonicalizes header keys).
+	Header string `json:"header"`
+
+	// Regexp is matched against each value of Header.
+	Regexp string `json:"regexp"`
+
+	// AllowMissing treats a missing header as passing rather than failing.
+	AllowMissing bool `json:"allow_missing,omitempty"`
+}
+
+// HTTPValidation configures response validation matchers for
+// PerformHTTPRequest/PerformHTTPRequestStream. Body matchers are compiled
+// once per request and streamed against the response body instead of
+// requiring the full body in memory, and run against the full body even
+// when the body returned on HTTPResponse is truncated by MaxBodySize.
+type HTTPValidation struct {
+	// FailIfBodyMatchesRegexp fails validation if any pattern matches the
+	// body.
+	FailIfBodyMatchesRegexp []string `json:"fail_if_body_matches_regexp,omitempty"`
+
+	// FailIfBodyNotMatchesRegexp fails validation if any pattern never
+	// matches anywhere in the body.
+	FailIfBodyNotMatchesRegexp []string `json:"fail_if_body_not_matches_regexp,omitempty"`
+
+	// FailIfHeaderMatches fails validation if a header value matches.
+	FailIfHeaderMatches []HeaderMatcher `json:"fail_if_header_matches,omitempty"`
+
+	// FailIfHeaderNotMatches fails validation if a header's values never
+	// match.
+	FailIfHeaderNotMatches []HeaderMatcher `json:"fail_if_header_not_matches,omitempty"`
+
+	// ValidStatusCodes fails validation if the response status code isn't
+	// in this list. Empty means any status code passes.
+	ValidStatusCodes []int `json:"valid_status_codes,omitempty"`
+
+	// ValidHTTPVersions fails validation if the response proto (e.g.
+	// "HTTP/1.1") isn't in this list. Empty means any version passes.
+	ValidHTTPVersions []string `json:"valid_http_versions,omitempty"`
+}
+
+// isZero reports whether v has no matchers configured, letting callers skip
+// validation entirely.
+func (v HTTPValidation) isZero() bool {
+	return len(v.FailIfBodyMatchesRegexp) == 0 &&
+		len(v.FailIfBodyNotMatchesRegexp) == 0 &&
+		len(v.FailIfHeaderMatches) == 0 &&
+		len(v.FailIfHeaderNotMatches) == 0 &&
+		len(v.ValidStatusCodes) == 0 &&
+		len(v.ValidHTTPVersions) == 0
+}
+
+// HTTPValidationResult reports which validation matcher, if any, tripped.
+type HTTPValidationResult struct {
+	// FailedMatcher names the matcher that failed, e.g. "status_code",
+	// "http_version", "header_matches:X-Foo", "body_matches:<pattern>", or
+	// "body_not_matches:<pattern>", so plugins can branch on failure class.
+	FailedMatcher string `json:"failed_matcher,omitempty"`
+
+	// Matched is the snippet that tripped a body or header matcher, when
+	// applicable.
+	Matched string `json:"matched,omitempty"`
+
+	// Offset is the byte offset of Matched within the body, for body
+	// matchers.
+	Offset int64 `json:"offset,omitempty"`
+
+	// Failed is true whenever FailedMatcher is set; kept as an explicit
+	// field so a zero-value result reads unambiguously as "passed".
+	Failed bool `json:"failed"`
+}
+
+type bodyMatcher struct {
+	re          *regexp.Regexp
+	code        string
+	failOnMatch bool
+}
+
+// compileBodyMatchers compiles every body regexp in v once, up front, so a
+// malformed pattern is reported before any bytes are read off the wire.
+func compileBodyMatchers(v HTTPValidation) ([]bodyMatcher, error) {
+	var out []bodyMatcher
+	for _, pattern := range v.FailIfBodyMatchesRegexp {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("hostfuncs: invalid FailIfBodyMatchesRegexp %q: %w", pattern, err)
+		}
+		out = append(out, bodyMatcher{re: re, code: "body_matches:" + pattern, failOnMatch: true})
+	}
+	for _, pattern := range v.FailIfBodyNotMatchesRegexp {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("hostfuncs: invalid FailIfBodyNotMatchesRegexp %q: %w", pattern, err)
+		}
+		out = append(out, bodyMatcher{re: re, code: "body_not_matches:" + pattern, failOnMatch: false})
+	}
+	return out, nil
+}
+
+// validateStatusAndHeaders checks the cheap, non-streaming matchers: status
+// code, HTTP version, and header matchers. Returns nil if everything passes.
+func validateStatusAndHeaders(resp *HTTPStreamResponse, v HTTPValidation) *HTTPValidationResult {
+	if len(v.ValidStatusCodes) > 0 && !intInSlice(v.ValidStatusCodes, resp.StatusCode) {
+		return &HTTPValidationResult{Failed: true, FailedMatcher: "status_code"}
+	}
+	if len(v.ValidHTTPVersions) > 0 && !stringInSlice(v.ValidHTTPVersions, resp.Proto) {
+		return &HTTPValidationResult{Failed: true, FailedMatcher: "http_version"}
+	}
+	for _, hm := range v.FailIfHeaderMatches {
+		if res := checkHeaderMatcher(resp.Headers, hm, true); res != nil {
+			return res
+		}
+	}
+	for _, hm := range v.FailIfHeaderNotMatches {
+		if res := checkHeaderMatcher(resp.Headers, hm, false); res != nil {
+			return res
+		}
+	}
+	return nil
+}
+
+func checkHeaderMatcher(headers map[string][]string, hm HeaderMatcher, failOnMatch bool) *HTTPValidationResult {
+	re, err := regexp.Compile(hm.Regexp)
+	if err != nil {
+		return &HTTPValidationResult{
+			Failed:        true,
+			FailedMatcher: "header_matches:" + hm.Header,
+			Matched:       fmt.Sprintf("invalid regexp: %v", err),
+		}
+	}
+
+	code := "header_matches:" + hm.Header
+	if !failOnMatch {
+		code = "header_not_matches:" + hm.Header
+	}
+
+	values := headers[http.CanonicalHeaderKey(hm.Header)]
+	if len(values) == 0 {
+		if hm.AllowMissing || failOnMatch {
+			return nil
+		}
+		return &HTTPValidationResult{Failed: true, FailedMatcher: code}
+	}
+
+	for _, v := range values {
+		matched := re.MatchString(v)
+		if failOnMatch && matched {
+			return &HTTPValidationResult{Failed: true, FailedMatcher: code, Matched: v}
+		}
+		if !failOnMatch && matched {
+			return nil
+		}
+	}
+	if !failOnMatch {
+		return &HTTPValidationResult{Failed: true, FailedMatcher: code}
+	}
+	return nil
+}
+
+// drainAndValidate reads body once, producing the (possibly truncated,
+// up to bufLimit) buffer returned on HTTPResponse while running matchers
+// against the full, untruncated stream via a bounded sliding window
+// (bodyMatchWindow), so a pattern past the truncation point still trips
+// validation without requiring the full body in memory.
+func drainAndValidate(body io.Reader, bufLimit int64, matchers []bodyMatcher) (buffered []byte, truncated bool, result *HTTPValidationResult, err error) {
+	chunk := make([]byte, bodyMatchReadSize)
+	win := make([]byte, 0, bodyMatchWindow+bodyMatchReadSize)
+	var windowStart int64
+	var total int64
+	matchedOnce := make([]bool, len(matchers))
+
+	for {
+		n, readErr := body.Read(chunk)
+		if n > 0 {
+			total += int64(n)
+
+			if int64(len(buffered)) <= bufLimit {
+				room := bufLimit + 1 - int64(len(buffered))
+				take := int64(n)
+				if take > room {
+					take = room
+				}
+				if take > 0 {
+					buffered = append(buffered, chunk[:take]...)
+				}
+			}
+
+			if result == nil {
+				win = append(win, chunk[:n]...)
+				for i, m := range matchers {
+					if matchedOnce[i] {
+						continue
+					}
+					if loc := m.re.FindIndex(win); loc != nil {
+						matchedOnce[i] = true
+						if m.failOnMatch {
+							result = &HTTPValidationResult{
+								Failed:        true,
+								FailedMatcher: m.code,
+								Matched:       string(win[loc[0]:loc[1]]),
+								Offset:        windowStart + int64(loc[0]),
+							}
+						}
+					}
+				}
+				if len(win) > bodyMatchWindow {
+					trim := len(win) - bodyMatchWindow
+					win = win[trim:]
+					windowStart += int64(trim)
+				}
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return buffered, int64(len(buffered)) > bufLimit, result, readErr
+		}
+		if total >= maxBodyValidationScan {
+			break
+		}
+	}
+
+	truncated = int64(len(buffered)) > bufLimit
+	if truncated {
+		buffered = buffered[:bufLimit]
+	}
+
+	if result == nil {
+		for i, m := range matchers {
+			if !m.failOnMatch && !matchedOnce[i] {
+				result = &HTTPValidationResult{Failed: true, FailedMatcher: m.code}
+				break
+			}
+		}
+	}
+
+	return buffered, truncated, result, nil
+}
+
+func intInSlice(haystack []int, needle int) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func stringInSlice(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package hostfuncs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformHTTPRequest_ValidStatusCodes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			ValidStatusCodes: []int{http.StatusOK},
+		},
+	})
+
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "status_code", resp.Validation.FailedMatcher)
+}
+
+func TestPerformHTTPRequest_ValidHTTPVersions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			ValidHTTPVersions: []string{"HTTP/2.0"},
+		},
+	})
+
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "http_version", resp.Validation.FailedMatcher)
+}
+
+func TestPerformHTTPRequest_HeaderMatches(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Powered-By", "vulnerable-framework/1.0")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfHeaderMatches: []HeaderMatcher{
+				{Header: "X-Powered-By", Regexp: "vulnerable"},
+			},
+		},
+	})
+
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "header_matches:X-Powered-By", resp.Validation.FailedMatcher)
+	assert.Equal(t, "vulnerable-framework/1.0", resp.Validation.Matched)
+}
+
+func TestPerformHTTPRequest_HeaderNotMatches_MissingHeaderFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfHeaderNotMatches: []HeaderMatcher{
+				{Header: "X-Frame-Options", Regexp: "DENY"},
+			},
+		},
+	})
+
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "header_not_matches:X-Frame-Options", resp.Validation.FailedMatcher)
+}
+
+func TestPerformHTTPRequest_HeaderNotMatches_AllowMissingPasses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfHeaderNotMatches: []HeaderMatcher{
+				{Header: "X-Frame-Options", Regexp: "DENY", AllowMissing: true},
+			},
+		},
+	})
+
+	assert.Nil(t, resp.Validation)
+}
+
+func TestPerformHTTPRequest_BodyMatchesRegexp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("internal server error: stack trace follows"))
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfBodyMatchesRegexp: []string{"stack trace"},
+		},
+	})
+
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "body_matches:stack trace", resp.Validation.FailedMatcher)
+	assert.Equal(t, "stack trace", resp.Validation.Matched)
+}
+
+func TestPerformHTTPRequest_BodyNotMatchesRegexp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("all good here"))
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfBodyNotMatchesRegexp: []string{"ok|healthy"},
+		},
+	})
+
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "body_not_matches:ok|healthy", resp.Validation.FailedMatcher)
+}
+
+func TestPerformHTTPRequest_BodyMatchPastTruncationPoint(t *testing.T) {
+	body := strings.Repeat("a", 100) + "needle" + strings.Repeat("b", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfBodyMatchesRegexp: []string{"needle"},
+		},
+	}, WithHTTPMaxBodySize(10))
+
+	require.True(t, resp.BodyTruncated)
+	require.NotNil(t, resp.Validation)
+	assert.True(t, resp.Validation.Failed)
+	assert.Equal(t, "body_matches:needle", resp.Validation.FailedMatcher)
+	assert.Equal(t, int64(100), resp.Validation.Offset)
+}
+
+func TestPerformHTTPRequest_InvalidBodyRegexp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{
+		Method: "GET",
+		URL:    srv.URL,
+		Validation: HTTPValidation{
+			FailIfBodyMatchesRegexp: []string{"("},
+		},
+	})
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "INVALID_VALIDATION_CONFIG", resp.Error.Code)
+}
+
+func TestPerformHTTPRequest_NoValidationConfigured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL})
+
+	assert.Nil(t, resp.Validation)
+}
@@ -0,0 +1,266 @@
+package hostfuncs
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// retryAttemptContextKey marks a request context as belonging to a retry
+// attempt (i.e. not the first try), set by performHTTPRequestWithRetry.
+type retryAttemptContextKey struct{}
+
+func isRetryAttempt(req *http.Request) bool {
+	v, _ := req.Context().Value(retryAttemptContextKey{}).(bool)
+	return v
+}
+
+// HTTPRoundTripper is the interface implemented by the dial+do path used to
+// execute a request. It matches http.RoundTripper's method signature so that
+// an *http.Transport (including an SSRF-protected one) can be used directly
+// as the innermost HTTPRoundTripper in a middleware chain.
+type HTTPRoundTripper interface {
+	RoundTrip(req *http.Request) (*http.Response, error)
+}
+
+// HTTPMiddleware wraps an HTTPRoundTripper to add cross-cutting behavior
+// (logging, metrics, circuit-breaking, header injection, ...) around a
+// request without forking PerformHTTPRequest. Middlewares are applied
+// around the SSRF-protected transport, so they can observe and modify
+// requests/responses but cannot replace the pinned dialer underneath.
+type HTTPMiddleware func(next HTTPRoundTripper) HTTPRoundTripper
+
+// WithHTTPMiddleware registers middlewares to wrap every request made
+// through PerformHTTPRequest. Middlewares are applied in the order given,
+// so the first middleware is outermost (sees the request first, the
+// response last).
+func WithHTTPMiddleware(mw ...HTTPMiddleware) HTTPOption {
+	return func(c *httpConfig) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// roundTripperFunc adapts a function to an HTTPRoundTripper.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NewLoggingMiddleware returns a middleware that logs each request and its
+// outcome as a structured slog record. If logger is nil, slog.Default() is used.
+func NewLoggingMiddleware(logger *slog.Logger) HTTPMiddleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next HTTPRoundTripper) HTTPRoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			attrs := []any{
+				slog.String("method", req.Method),
+				slog.String("url", req.URL.String()),
+				slog.Duration("latency", time.Since(start)),
+			}
+			if err != nil {
+				logger.Error("http request failed", append(attrs, slog.String("error", err.Error()))...)
+				return resp, err
+			}
+			logger.Info("http request completed", append(attrs, slog.Int("status_code", resp.StatusCode))...)
+			return resp, nil
+		})
+	}
+}
+
+// HTTPMetrics accumulates Prometheus-style counters and a latency histogram
+// for requests made through PerformHTTPRequest. It is safe for concurrent use.
+type HTTPMetrics struct {
+	mu             sync.Mutex
+	latencyBuckets map[float64]uint64 // upper bound (seconds) -> count
+	latencyCount   uint64
+	latencySum     float64
+	statusCounts   map[int]uint64
+	errorCount     uint64
+	retryCount     uint64
+}
+
+// defaultLatencyBucketsSeconds mirrors a typical Prometheus histogram for
+// HTTP client latency.
+var defaultLatencyBucketsSeconds = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewHTTPMetrics creates a metrics collector with default latency buckets.
+func NewHTTPMetrics() *HTTPMetrics {
+	buckets := make(map[float64]uint64, len(defaultLatencyBucketsSeconds))
+	for _, b := range defaultLatencyBucketsSeconds {
+		buckets[b] = 0
+	}
+	return &HTTPMetrics{
+		latencyBuckets: buckets,
+		statusCounts:   make(map[int]uint64),
+	}
+}
+
+func (m *HTTPMetrics) observe(req *http.Request, resp *http.Response, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latencyCount++
+	m.latencySum += latency.Seconds()
+	for bound := range m.latencyBuckets {
+		if latency.Seconds() <= bound {
+			m.latencyBuckets[bound]++
+		}
+	}
+
+	if isRetryAttempt(req) {
+		m.retryCount++
+	}
+
+	if err != nil {
+		m.errorCount++
+		return
+	}
+	m.statusCounts[resp.StatusCode]++
+}
+
+// Snapshot returns a point-in-time copy of the collected metrics, safe to
+// read and render without holding the collector's lock.
+func (m *HTTPMetrics) Snapshot() HTTPMetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	buckets := make(map[float64]uint64, len(m.latencyBuckets))
+	for k, v := range m.latencyBuckets {
+		buckets[k] = v
+	}
+	statuses := make(map[int]uint64, len(m.statusCounts))
+	for k, v := range m.statusCounts {
+		statuses[k] = v
+	}
+
+	return HTTPMetricsSnapshot{
+		LatencyBuckets: buckets,
+		LatencyCount:   m.latencyCount,
+		LatencySumSecs: m.latencySum,
+		StatusCounts:   statuses,
+		ErrorCount:     m.errorCount,
+		RetryCount:     m.retryCount,
+	}
+}
+
+// HTTPMetricsSnapshot is an immutable view of HTTPMetrics suitable for
+// rendering or assertions in tests.
+type HTTPMetricsSnapshot struct {
+	LatencyBuckets map[float64]uint64
+	LatencyCount   uint64
+	LatencySumSecs float64
+	StatusCounts   map[int]uint64
+	ErrorCount     uint64
+	RetryCount     uint64
+}
+
+// NewMetricsMiddleware returns a middleware that records request outcomes
+// into m.
+func NewMetricsMiddleware(m *HTTPMetrics) HTTPMiddleware {
+	return func(next HTTPRoundTripper) HTTPRoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			m.observe(req, resp, time.Since(start), err)
+			return resp, err
+		})
+	}
+}
+
+// NewHeaderInjectionMiddleware returns a middleware that sets the given
+// headers on every outgoing request (e.g. auth tokens, a fixed User-Agent).
+// Existing header values set by the caller are overwritten.
+func NewHeaderInjectionMiddleware(headers map[string]string) HTTPMiddleware {
+	return func(next HTTPRoundTripper) HTTPRoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// CircuitBreakerConfig configures per-host circuit breaking.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures (errors or
+	// retryable status codes >= 500) after which a host's circuit opens.
+	FailureThreshold int
+
+	// OpenDuration is how long the circuit stays open before allowing a
+	// single trial request through (half-open).
+	OpenDuration time.Duration
+}
+
+func defaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+type circuitState struct {
+	consecutiveFailures int
+	openUntil           time.Time
+}
+
+// ErrCircuitOpen is returned by the circuit-breaker middleware when a host's
+// circuit is open and the request is short-circuited without being sent.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker open for host")
+
+// NewCircuitBreakerMiddleware returns a middleware that opens a per-host
+// circuit after FailureThreshold consecutive failures, short-circuiting
+// further requests to that host until OpenDuration elapses. Zero-value
+// fields in cfg fall back to defaultCircuitBreakerConfig.
+func NewCircuitBreakerMiddleware(cfg CircuitBreakerConfig) HTTPMiddleware {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultCircuitBreakerConfig().FailureThreshold
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = defaultCircuitBreakerConfig().OpenDuration
+	}
+
+	var mu sync.Mutex
+	states := make(map[string]*circuitState)
+
+	return func(next HTTPRoundTripper) HTTPRoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			host := req.URL.Hostname()
+
+			mu.Lock()
+			st, ok := states[host]
+			if !ok {
+				st = &circuitState{}
+				states[host] = st
+			}
+			if !st.openUntil.IsZero() && time.Now().Before(st.openUntil) {
+				mu.Unlock()
+				return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+			}
+			mu.Unlock()
+
+			resp, err := next.RoundTrip(req)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil || (resp != nil && resp.StatusCode >= 500) {
+				st.consecutiveFailures++
+				if st.consecutiveFailures >= cfg.FailureThreshold {
+					st.openUntil = time.Now().Add(cfg.OpenDuration)
+				}
+			} else {
+				st.consecutiveFailures = 0
+				st.openUntil = time.Time{}
+			}
+
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package hostfuncs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHTTPMiddleware_HeaderInjectionAndOrdering(t *testing.T) {
+	var seenAuth, seenUA string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenAuth = r.Header.Get("Authorization")
+		seenUA = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var order []string
+	trace := func(name string) HTTPMiddleware {
+		return func(next HTTPRoundTripper) HTTPRoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPMiddleware(
+			trace("outer"),
+			NewHeaderInjectionMiddleware(map[string]string{
+				"Authorization": "Bearer token",
+				"User-Agent":    "reglet-plugin-sdk",
+			}),
+			trace("inner"),
+		),
+	)
+
+	require.Nil(t, resp.Error)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Bearer token", seenAuth)
+	assert.Equal(t, "reglet-plugin-sdk", seenUA)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+}
+
+func TestWithHTTPMiddleware_MetricsRecordsStatusAndRetries(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	metrics := NewHTTPMetrics()
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPMiddleware(NewMetricsMiddleware(metrics)),
+		WithHTTPRetry(RetryConfig{InitialInterval: 0, MaxInterval: 0, MaxElapsedTime: 0}),
+	)
+
+	require.Nil(t, resp.Error)
+	snap := metrics.Snapshot()
+	assert.EqualValues(t, 2, snap.LatencyCount)
+	assert.EqualValues(t, 1, snap.StatusCounts[http.StatusOK])
+	assert.EqualValues(t, 1, snap.StatusCounts[http.StatusServiceUnavailable])
+	assert.EqualValues(t, 1, snap.RetryCount)
+}
+
+func TestNewCircuitBreakerMiddleware_OpensAfterThreshold(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreakerMiddleware(CircuitBreakerConfig{FailureThreshold: 2})
+
+	for i := 0; i < 2; i++ {
+		resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+			WithHTTPMiddleware(cb),
+		)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+
+	resp := PerformHTTPRequest(context.Background(), HTTPRequest{Method: "GET", URL: srv.URL},
+		WithHTTPMiddleware(cb),
+	)
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "circuit breaker open")
+}
@@ -0,0 +1,142 @@
+//go:build wasip1
+
+package wasm
+
+import (
+	"encoding/json"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/entities"
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/ports"
+	"github.com/reglet-dev/reglet-plugin-sdk/internal/abi"
+)
+
+// Define the host function signature for emitting a single log record. This
+// matches the signature defined in internal/wasm/hostfuncs/registry.go,
+// next to trace_export.
+//
+//go:wasmimport reglet_host log_emit
+func host_log_emit(recordPacked uint64)
+
+// Compile-time interface compliance check
+var _ ports.Logger = (*LoggerAdapter)(nil)
+
+// logLevelRank orders level names so Enabled can compare a record's level
+// against the capability's MinLevel.
+var logLevelRank = map[string]int{
+	"trace": 0,
+	"debug": 1,
+	"info":  2,
+	"warn":  3,
+	"error": 4,
+}
+
+// LoggerAdapter implements ports.Logger for the WASM environment, filtering
+// records against a LoggingCapability's MinLevel and Fields allow-list
+// before JSON-marshalling and shipping them to the host via host_log_emit.
+type LoggerAdapter struct {
+	capability entities.LoggingCapability
+	name       string
+	kv         []any
+}
+
+// NewLoggerAdapter creates a logger bound to capability, which bounds the
+// minimum level and the structured attribute keys it is allowed to emit.
+func NewLoggerAdapter(capability entities.LoggingCapability) *LoggerAdapter {
+	return &LoggerAdapter{capability: capability}
+}
+
+// logRecordWire is the JSON wire format for a single log record sent to the
+// host.
+type logRecordWire struct {
+	Level string         `json:"level"`
+	Name  string         `json:"name,omitempty"`
+	Msg   string         `json:"msg"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+func (l *LoggerAdapter) Trace(msg string, kv ...any) { l.emit("trace", msg, kv) }
+func (l *LoggerAdapter) Debug(msg string, kv ...any) { l.emit("debug", msg, kv) }
+func (l *LoggerAdapter) Info(msg string, kv ...any)  { l.emit("info", msg, kv) }
+func (l *LoggerAdapter) Warn(msg string, kv ...any)  { l.emit("warn", msg, kv) }
+func (l *LoggerAdapter) Error(msg string, kv ...any) { l.emit("error", msg, kv) }
+
+func (l *LoggerAdapter) With(kv ...any) ports.Logger {
+	merged := make([]any, 0, len(l.kv)+len(kv))
+	merged = append(merged, l.kv...)
+	merged = append(merged, kv...)
+	return &LoggerAdapter{capability: l.capability, name: l.name, kv: merged}
+}
+
+func (l *LoggerAdapter) Named(name string) ports.Logger {
+	qualified := name
+	if l.name != "" {
+		qualified = l.name + "." + name
+	}
+	return &LoggerAdapter{capability: l.capability, name: qualified, kv: l.kv}
+}
+
+// enabled reports whether level meets the capability's MinLevel. An unknown
+// level name, on either side, is treated as enabled rather than silently
+// dropped.
+func (l *LoggerAdapter) enabled(level string) bool {
+	if l.capability.MinLevel == "" {
+		return true
+	}
+	rank, known := logLevelRank[level]
+	minRank, minKnown := logLevelRank[l.capability.MinLevel]
+	if !known || !minKnown {
+		return true
+	}
+	return rank >= minRank
+}
+
+// allowed reports whether key may be emitted under the capability's Fields
+// allow-list. An empty allow-list permits nothing; "*" permits everything.
+func (l *LoggerAdapter) allowed(key string) bool {
+	for _, f := range l.capability.Fields {
+		if f == "*" || f == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *LoggerAdapter) emit(level, msg string, kv []any) {
+	if !l.enabled(level) {
+		return
+	}
+
+	attrs := make(map[string]any)
+	addPairs := func(pairs []any) {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok || !l.allowed(key) {
+				continue
+			}
+			attrs[key] = pairs[i+1]
+		}
+	}
+	addPairs(l.kv)
+	addPairs(kv)
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+
+	record := logRecordWire{
+		Level: level,
+		Name:  l.name,
+		Msg:   msg,
+		Attrs: attrs,
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	packed, err := abi.PtrFromBytes(recordBytes)
+	if err != nil {
+		return
+	}
+	host_log_emit(packed)
+}
@@ -19,11 +19,13 @@ import (
 var _ ports.TCPDialer = (*TCPAdapter)(nil)
 
 // TCPAdapter implements ports.TCPDialer for the WASM environment.
-type TCPAdapter struct{}
+type TCPAdapter struct {
+	tracer ports.Tracer
+}
 
 // NewTCPAdapter creates a new TCP adapter.
 func NewTCPAdapter() *TCPAdapter {
-	return &TCPAdapter{}
+	return &TCPAdapter{tracer: &TracerAdapter{}}
 }
 
 // Dial establishes a TCP connection to the given address.
@@ -38,10 +40,16 @@ func (a *TCPAdapter) DialWithTimeout(ctx context.Context, address string, timeou
 
 // DialSecure establishes a TCP connection with timeout and optional TLS.
 func (a *TCPAdapter) DialSecure(ctx context.Context, address string, timeoutMs int, tls bool) (ports.TCPConnection, error) {
+	span, ctx := a.tracer.StartSpan(ctx, "tcp.dial")
+	defer span.End()
+
 	host, port, err := net.SplitHostPort(address)
 	if err != nil {
+		span.SetError(err)
 		return nil, fmt.Errorf("invalid address: %w", err)
 	}
+	span.SetAttribute("net.peer.name", host)
+	span.SetAttribute("net.peer.port", port)
 
 	request := entities.TCPRequest{
 		Context:   wasmcontext.ContextToWire(ctx),
@@ -70,9 +78,14 @@ func (a *TCPAdapter) DialSecure(ctx context.Context, address string, timeoutMs i
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("%s: %s", response.Error.Type, response.Error.Message)
+		err := fmt.Errorf("%s: %s", response.Error.Type, response.Error.Message)
+		span.SetError(err)
+		return nil, err
 	}
 
+	span.SetAttribute("tls.version", response.TLSVersion)
+	span.SetAttribute("tls.cipher_suite", response.TLSCipherSuite)
+
 	return &WasmTCPConnection{
 		response: response,
 	}, nil
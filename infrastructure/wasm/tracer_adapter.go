@@ -0,0 +1,126 @@
+//go:build wasip1
+
+package wasm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/reglet-dev/reglet-plugin-sdk/domain/ports"
+	"github.com/reglet-dev/reglet-plugin-sdk/internal/abi"
+)
+
+// Define the host function signature for exporting a finished span. This
+// matches the signature defined in internal/wasm/hostfuncs/registry.go,
+// next to tcp_connect.
+//
+//go:wasmimport reglet_host trace_export
+func host_trace_export(spanPacked uint64)
+
+// Compile-time interface compliance check
+var _ ports.Tracer = (*TracerAdapter)(nil)
+
+// TracerAdapter implements ports.Tracer for the WASM environment, exporting
+// finished spans to the host via host_trace_export in the W3C traceparent
+// format so plugin execution can be stitched into the host's trace pipeline.
+type TracerAdapter struct{}
+
+// traceSpanWire is the JSON wire format for a finished span sent to the
+// host.
+type traceSpanWire struct {
+	TraceParent string         `json:"traceparent"`
+	Name        string         `json:"name"`
+	StartTime   time.Time      `json:"start_time"`
+	EndTime     time.Time      `json:"end_time"`
+	Attrs       map[string]any `json:"attrs,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// spanContextKey is the value the active traceparent is stored under in a
+// context.Context, so nested calls can derive a child span from it.
+type spanContextKey struct{}
+
+// StartSpan begins a new span named name. If ctx already carries a
+// traceparent (from an outer StartSpan call), the new span is parented to it;
+// otherwise it starts a new trace.
+func (t *TracerAdapter) StartSpan(ctx context.Context, name string) (ports.Span, context.Context) {
+	traceID, spanID := newTraceID(ctx), newSpanID()
+	span := &wasmSpan{
+		traceParent: traceParentHeader(traceID, spanID),
+		name:        name,
+		start:       time.Now(),
+		attrs:       make(map[string]any),
+	}
+	return span, context.WithValue(ctx, spanContextKey{}, span.traceParent)
+}
+
+// wasmSpan implements ports.Span, buffering attributes until End exports the
+// completed span to the host.
+type wasmSpan struct {
+	traceParent string
+	name        string
+	start       time.Time
+	attrs       map[string]any
+	err         error
+}
+
+func (s *wasmSpan) SetAttribute(key string, value any) {
+	s.attrs[key] = value
+}
+
+func (s *wasmSpan) SetError(err error) {
+	s.err = err
+}
+
+func (s *wasmSpan) End() {
+	wire := traceSpanWire{
+		TraceParent: s.traceParent,
+		Name:        s.name,
+		StartTime:   s.start,
+		EndTime:     time.Now(),
+		Attrs:       s.attrs,
+	}
+	if s.err != nil {
+		wire.Error = s.err.Error()
+	}
+
+	spanBytes, err := json.Marshal(wire)
+	if err != nil {
+		return
+	}
+
+	packed, err := abi.PtrFromBytes(spanBytes)
+	if err != nil {
+		return
+	}
+	host_trace_export(packed)
+}
+
+// traceParentHeader formats traceID and spanID per the W3C Trace Context
+// spec (version "00", flags "01" - sampled).
+func traceParentHeader(traceID, spanID string) string {
+	return "00-" + traceID + "-" + spanID + "-01"
+}
+
+// newTraceID returns the trace ID embedded in ctx's traceparent, if any,
+// otherwise generates a fresh 16-byte (32 hex char) trace ID.
+func newTraceID(ctx context.Context) string {
+	if parent, ok := ctx.Value(spanContextKey{}).(string); ok && len(parent) >= 35 {
+		return parent[3:35]
+	}
+	return randomHex(16)
+}
+
+// newSpanID generates a fresh 8-byte (16 hex char) span ID.
+func newSpanID() string {
+	return randomHex(8)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}